@@ -0,0 +1,71 @@
+package hosttech
+
+import (
+	"context"
+	"sync"
+
+	"github.com/libdns/libdns"
+)
+
+// runBatch calls task for every index from 0 to n-1, with at most MaxConcurrency calls running at once. Once a task
+// failed, no further tasks are started, unless ContinueOnError is set. The failures are returned in the order of
+// their indices.
+func (p *Provider) runBatch(ctx context.Context, n int, task func(i int) *RecordError) []RecordError {
+	workers := p.MaxConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	var (
+		mutex     sync.Mutex
+		waitGroup sync.WaitGroup
+		failed    bool
+	)
+	failures := make([]*RecordError, n)
+	semaphore := make(chan struct{}, workers)
+
+	for i := 0; i < n; i++ {
+		semaphore <- struct{}{}
+
+		mutex.Lock()
+		stop := failed && !p.continueOnError(ctx)
+		mutex.Unlock()
+		if stop {
+			<-semaphore
+			break
+		}
+
+		waitGroup.Add(1)
+		go func(i int) {
+			defer waitGroup.Done()
+			defer func() { <-semaphore }()
+
+			if failure := task(i); failure != nil {
+				mutex.Lock()
+				failures[i] = failure
+				failed = true
+				mutex.Unlock()
+			}
+		}(i)
+	}
+	waitGroup.Wait()
+
+	var orderedFailures []RecordError
+	for _, failure := range failures {
+		if failure != nil {
+			orderedFailures = append(orderedFailures, *failure)
+		}
+	}
+	return orderedFailures
+}
+
+// compactRecords drops the slots of records that were not processed successfully, keeping the order of the others
+func compactRecords(records []libdns.Record) []libdns.Record {
+	compacted := []libdns.Record{}
+	for _, record := range records {
+		if record != nil {
+			compacted = append(compacted, record)
+		}
+	}
+	return compacted
+}
@@ -0,0 +1,89 @@
+package hosttech
+
+import (
+	"context"
+	"errors"
+	"github.com/libdns/libdns"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProvider_RunBatch(t *testing.T) {
+	provider := &Provider{MaxConcurrency: 4}
+
+	var running, maxRunning int32
+	var mutex sync.Mutex
+	var order []int
+	failures := provider.runBatch(context.Background(), 20, func(i int) *RecordError {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			previous := atomic.LoadInt32(&maxRunning)
+			if current <= previous || atomic.CompareAndSwapInt32(&maxRunning, previous, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		mutex.Lock()
+		order = append(order, i)
+		mutex.Unlock()
+		return nil
+	})
+
+	assert.Empty(t, failures)
+	assert.Len(t, order, 20)
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(4))
+	assert.Greater(t, atomic.LoadInt32(&maxRunning), int32(1))
+}
+
+func TestProvider_RunBatchStopsAfterFailure(t *testing.T) {
+	input := map[string]struct {
+		continueOnError bool
+		expectedCalls   int32
+		expectedFailed  int
+	}{
+		"Stop at first failure": {continueOnError: false, expectedCalls: 3, expectedFailed: 1},
+		"Continue on error":     {continueOnError: true, expectedCalls: 10, expectedFailed: 3},
+	}
+
+	for name, testStruct := range input {
+		t.Run(name, func(t *testing.T) {
+			provider := &Provider{ContinueOnError: testStruct.continueOnError}
+
+			var calls int32
+			failures := provider.runBatch(context.Background(), 10, func(i int) *RecordError {
+				atomic.AddInt32(&calls, 1)
+				if i%3 == 2 {
+					return &RecordError{Record: libdns.TXT{Name: "failed"}, Err: errors.New("failed")}
+				}
+				return nil
+			})
+
+			assert.Equal(t, testStruct.expectedCalls, atomic.LoadInt32(&calls))
+			assert.Len(t, failures, testStruct.expectedFailed)
+		})
+	}
+}
+
+func TestProvider_AppendRecordsConcurrently(t *testing.T) {
+	_, provider := newFakeHosttechAPI(t, "example.com")
+	provider.MaxConcurrency = 5
+
+	var records []libdns.Record
+	for i := 0; i < 30; i++ {
+		records = append(records, libdns.TXT{Name: "record-" + strconv.Itoa(i), Text: "text", TTL: time.Hour})
+	}
+
+	appendedRecords, err := provider.AppendRecords(context.Background(), "example.com", records)
+
+	assert.NoError(t, err)
+	assert.Len(t, appendedRecords, 30)
+	for i, record := range appendedRecords {
+		assert.Equal(t, "record-"+strconv.Itoa(i), record.RR().Name)
+	}
+}
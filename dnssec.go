@@ -0,0 +1,172 @@
+package hosttech
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// Digest types of a DS record as defined in RFC 4034, RFC 4509 and RFC 6605
+const (
+	DSDigestTypeSHA1   uint8 = 1
+	DSDigestTypeSHA256 uint8 = 2
+	DSDigestTypeSHA384 uint8 = 4
+)
+
+// dnskeyFlagSEP marks a key signing key
+const dnskeyFlagSEP uint16 = 1
+
+// DNSSECInfo holds the public DNSSEC data of a signed zone, which has to be handed to the registrar
+type DNSSECInfo struct {
+	DSRecords []DSRecord `json:"ds_records"`
+	DNSKEYs   []DNSKEY   `json:"dnskeys"`
+}
+
+// DSRecord holds the data of a DS record
+type DSRecord struct {
+	KeyTag     uint16 `json:"key_tag"`
+	Algorithm  uint8  `json:"algorithm"`
+	DigestType uint8  `json:"digest_type"`
+	// Digest is the digest of the DNSKEY as uppercase hex string
+	Digest string `json:"digest"`
+}
+
+// String returns the presentation form of the DS data, e.g. "60485 5 1 2BB183AF...".
+func (d DSRecord) String() string {
+	return fmt.Sprintf("%d %d %d %s", d.KeyTag, d.Algorithm, d.DigestType, d.Digest)
+}
+
+// ZoneFileLine returns the DS record for the zone as a line of a zone file, which most registrars accept as is.
+func (d DSRecord) ZoneFileLine(zone string) string {
+	return fmt.Sprintf("%s. IN DS %s", RemoveTrailingDot(zone), d)
+}
+
+// DNSKEY holds the data of a DNSKEY record
+type DNSKEY struct {
+	Flags     uint16 `json:"flags"`
+	Protocol  uint8  `json:"protocol"`
+	Algorithm uint8  `json:"algorithm"`
+	// PublicKey is the base64 encoded public key
+	PublicKey string `json:"public_key"`
+}
+
+// String returns the presentation form of the DNSKEY data, e.g. "257 3 13 mdsswUyr3...".
+func (k DNSKEY) String() string {
+	return fmt.Sprintf("%d %d %d %s", k.Flags, k.Protocol, k.Algorithm, k.PublicKey)
+}
+
+// ZoneFileLine returns the DNSKEY record for the zone as a line of a zone file
+func (k DNSKEY) ZoneFileLine(zone string) string {
+	return fmt.Sprintf("%s. IN DNSKEY %s", RemoveTrailingDot(zone), k)
+}
+
+// IsKeySigningKey reports whether the secure entry point flag is set, i.e. whether the registrar needs this key
+func (k DNSKEY) IsKeySigningKey() bool {
+	return k.Flags&dnskeyFlagSEP != 0
+}
+
+// KeyTag computes the key tag of the DNSKEY as defined in RFC 4034, appendix B
+func (k DNSKEY) KeyTag() (uint16, error) {
+	rdata, err := k.rdata()
+	if err != nil {
+		return 0, err
+	}
+
+	var accumulator uint32
+	for i, b := range rdata {
+		if i&1 == 0 {
+			accumulator += uint32(b) << 8
+		} else {
+			accumulator += uint32(b)
+		}
+	}
+	accumulator += accumulator >> 16 & 0xffff
+
+	return uint16(accumulator & 0xffff), nil
+}
+
+// DS computes the DS record of the DNSKEY for the zone with the given digest type
+func (k DNSKEY) DS(zone string, digestType uint8) (DSRecord, error) {
+	var digest hash.Hash
+	switch digestType {
+	case DSDigestTypeSHA1:
+		digest = sha1.New()
+	case DSDigestTypeSHA256:
+		digest = sha256.New()
+	case DSDigestTypeSHA384:
+		digest = sha512.New384()
+	default:
+		return DSRecord{}, fmt.Errorf("DS digest type %d is not supported", digestType)
+	}
+
+	rdata, err := k.rdata()
+	if err != nil {
+		return DSRecord{}, err
+	}
+	keyTag, _ := k.KeyTag()
+
+	digest.Write(canonicalWireName(zone))
+	digest.Write(rdata)
+
+	return DSRecord{
+		KeyTag:     keyTag,
+		Algorithm:  k.Algorithm,
+		DigestType: digestType,
+		Digest:     strings.ToUpper(hex.EncodeToString(digest.Sum(nil))),
+	}, nil
+}
+
+// rdata returns the DNSKEY in wire format
+func (k DNSKEY) rdata() ([]byte, error) {
+	publicKey, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(k.PublicKey), ""))
+	if err != nil {
+		return nil, fmt.Errorf(`DNSKEY public key "%s" is not valid base64: %w`, k.PublicKey, err)
+	}
+
+	rdata := make([]byte, 4, 4+len(publicKey))
+	binary.BigEndian.PutUint16(rdata, k.Flags)
+	rdata[2] = k.Protocol
+	rdata[3] = k.Algorithm
+
+	return append(rdata, publicKey...), nil
+}
+
+// canonicalWireName returns the lowercase wire format of the domain name as used for DS digests
+func canonicalWireName(name string) []byte {
+	var wire []byte
+	for _, label := range strings.Split(strings.ToLower(RemoveTrailingDot(name)), ".") {
+		if label == "" {
+			continue
+		}
+		wire = append(wire, byte(len(label)))
+		wire = append(wire, label...)
+	}
+
+	return append(wire, 0)
+}
+
+// withDerivedDSRecords fills in SHA-256 DS records for the key signing keys, if Hosttech returned no DS records
+func (i DNSSECInfo) withDerivedDSRecords(zone string) (DNSSECInfo, error) {
+	if len(i.DSRecords) > 0 {
+		return i, nil
+	}
+
+	for _, key := range i.DNSKEYs {
+		if !key.IsKeySigningKey() {
+			continue
+		}
+		ds, err := key.DS(zone, DSDigestTypeSHA256)
+		if err != nil {
+			return i, err
+		}
+		i.DSRecords = append(i.DSRecords, ds)
+	}
+
+	return i, nil
+}
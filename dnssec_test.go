@@ -0,0 +1,71 @@
+package hosttech
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// rootKSK is the key signing key of the root zone, which was introduced in 2017
+var rootKSK = DNSKEY{
+	Flags:     257,
+	Protocol:  3,
+	Algorithm: 8,
+	PublicKey: "AwEAAaz/tAm8yTn4Mfeh5eyI96WSVexTBAvkMgJzkKTOiW1vkIbzxeF3+/4RgWOq7HrxRixHlFlExOLAJr5emLvN7SWXgnLh4+B5xQlNVz8Og8kv " +
+		"ArMtNROxVQuCaSnIDdD5LKyWbRd2n9WGe2R8PzgCmr3EgVLrjyBxWezF0jLHwVN8efS3rCj/EWgvIWgb9tarpVUDK/b58Da+sqqls3eNbuv7pr+e " +
+		"oZG+SrDK6nWeL3c6H5Apxz7LjVc1uTIdsIXxuOLYA4/ilBmSVIzuDWfdRUfhHdY6+cn8HFRm+2hM8AnXGXws9555KrUB5qihylGa8subX2Nn6UwN " +
+		"R1AkUTV74bU=",
+}
+
+func TestDNSKEY_KeyTag(t *testing.T) {
+	keyTag, err := rootKSK.KeyTag()
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(20326), keyTag)
+}
+
+func TestDNSKEY_DS(t *testing.T) {
+	input := map[string]struct {
+		expectedResult DSRecord
+		expectedError  bool
+		digestType     uint8
+	}{
+		"SHA-256": {
+			expectedResult: DSRecord{KeyTag: 20326, Algorithm: 8, DigestType: 2, Digest: "E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D"},
+			digestType:     DSDigestTypeSHA256,
+		},
+		"Unsupported digest type": {
+			expectedError: true,
+			digestType:    3,
+		},
+	}
+
+	for name, testStruct := range input {
+		t.Run(name, func(t *testing.T) {
+			output, err := rootKSK.DS(".", testStruct.digestType)
+
+			if testStruct.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testStruct.expectedResult, output)
+		})
+	}
+}
+
+func TestDSRecord_ZoneFileLine(t *testing.T) {
+	ds := DSRecord{KeyTag: 60485, Algorithm: 5, DigestType: 1, Digest: "2BB183AF5F22588179A53B0A98631FAD1A292118"}
+
+	assert.Equal(t, "dskey.example.com. IN DS 60485 5 1 2BB183AF5F22588179A53B0A98631FAD1A292118", ds.ZoneFileLine("dskey.example.com"))
+}
+
+func TestDNSSECInfo_withDerivedDSRecords(t *testing.T) {
+	zsk := rootKSK
+	zsk.Flags = 256
+	info := DNSSECInfo{DNSKEYs: []DNSKEY{zsk, rootKSK}}
+
+	output, err := info.withDerivedDSRecords(".")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []DSRecord{{KeyTag: 20326, Algorithm: 8, DigestType: 2, Digest: "E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D"}}, output.DSRecords)
+}
@@ -0,0 +1,209 @@
+package hosttech
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// Sentinel errors to check the class of an error with errors.Is. Errors of the Hosttech API are returned as ApiError,
+// which matches the sentinel of its status code.
+var (
+	// ErrUnauthorized is returned if the API token is missing or invalid
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden is returned if the API token has no access to the requested resource
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound is returned if the requested zone or record does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrZoneNotFound is returned if the zone of the request does not exist. It also matches ErrNotFound.
+	ErrZoneNotFound error = subclassError{s: "zone not found", class: ErrNotFound}
+	// ErrZoneNotEmpty is returned by DeleteZone if the zone still contains records and the deletion was not forced
+	ErrZoneNotEmpty = errors.New("zone not empty")
+	// ErrRateLimited is returned if Hosttech rejected the request because of too many requests
+	ErrRateLimited = errors.New("rate limited")
+	// ErrValidation is returned if the request contained invalid data
+	ErrValidation = errors.New("validation failed")
+	// ErrServer is returned if the Hosttech API failed with a server error
+	ErrServer = errors.New("server error")
+	// ErrUnsupportedRecordType is returned for record types that can not be written with this package
+	ErrUnsupportedRecordType = errors.New("unsupported record type")
+)
+
+// subclassError is a sentinel error that also matches the sentinel of its broader class
+type subclassError struct {
+	s     string
+	class error
+}
+
+func (s subclassError) Error() string {
+	return s.s
+}
+
+func (s subclassError) Unwrap() error {
+	return s.class
+}
+
+// ApiError is returned when the Hosttech API answers with an unsuccessful status code.
+// If the response contained a JSON error payload, its message and the errors per field are exposed as well.
+type ApiError struct {
+	s         string
+	ErrorCode int
+	// Message is the error message sent by Hosttech, if any
+	Message string
+	// Fields holds the validation errors per field of the request, e.g. {"ipv4": ["The ipv4 must be a valid IPv4 address."]}
+	Fields map[string][]string
+	// Body is the raw body of the response
+	Body []byte
+	// zoneNotFound is set if the 404 status code refers to the zone of the request and not to a record
+	zoneNotFound bool
+}
+
+// hosttechErrorResponse is the error payload of the Hosttech API
+type hosttechErrorResponse struct {
+	Message string              `json:"message"`
+	Errors  map[string][]string `json:"errors"`
+}
+
+func newApiError(status string, statusCode int, body []byte) ApiError {
+	apiError := ApiError{
+		s:         fmt.Sprintf("call to API was not successful, returned the status code '%s'", status),
+		ErrorCode: statusCode,
+		Body:      body,
+	}
+
+	var errorResponse hosttechErrorResponse
+	if json.Unmarshal(body, &errorResponse) == nil {
+		apiError.Message = errorResponse.Message
+		apiError.Fields = errorResponse.Errors
+	}
+
+	return apiError
+}
+
+func (a ApiError) Error() string {
+	var builder strings.Builder
+	builder.WriteString(a.s)
+	if a.Message != "" {
+		builder.WriteString(": ")
+		builder.WriteString(a.Message)
+	}
+
+	fields := make([]string, 0, len(a.Fields))
+	for field := range a.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		fmt.Fprintf(&builder, " (%s: %s)", field, strings.Join(a.Fields[field], " "))
+	}
+
+	return builder.String()
+}
+
+// Is reports whether the error belongs to the error class of target, e.g. ErrNotFound for a 404 status code.
+func (a ApiError) Is(target error) bool {
+	return target == ErrZoneNotFound && a.zoneNotFound
+}
+
+// Unwrap returns the sentinel error matching the status code, or nil if there is none
+func (a ApiError) Unwrap() error {
+	switch {
+	case a.ErrorCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case a.ErrorCode == http.StatusForbidden:
+		return ErrForbidden
+	case a.ErrorCode == http.StatusNotFound:
+		return ErrNotFound
+	case a.ErrorCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case a.ErrorCode == http.StatusBadRequest || a.ErrorCode == http.StatusUnprocessableEntity:
+		return ErrValidation
+	case a.ErrorCode >= 500:
+		return ErrServer
+	default:
+		return nil
+	}
+}
+
+// zoneError marks a 404 error of a zone scoped call as ErrZoneNotFound
+func zoneError(err error) error {
+	var apiError ApiError
+	if errors.As(err, &apiError) && apiError.ErrorCode == http.StatusNotFound {
+		apiError.zoneNotFound = true
+		return apiError
+	}
+	return err
+}
+
+// RecordError is the failure of a single record of a batch operation
+type RecordError struct {
+	// Record is the input record that failed, or the existing record if deleting it failed
+	Record libdns.Record
+	action string
+	Err    error
+}
+
+func (r RecordError) Error() string {
+	rr := r.Record.RR()
+	return fmt.Sprintf(`could not %s record "%s %d %s %s": %s`, r.action, rr.Name, int(rr.TTL.Seconds()), rr.Type, rr.Data, r.Err)
+}
+
+func (r RecordError) Unwrap() error {
+	return r.Err
+}
+
+// recordError adds the record that caused the error to it, to tell which record of a batch failed
+func recordError(action string, record libdns.Record, err error) RecordError {
+	return RecordError{Record: record, action: action, Err: err}
+}
+
+// BatchError is returned by AppendRecords, SetRecords and DeleteRecords if single records failed.
+// The records that were processed successfully are returned along with it.
+type BatchError struct {
+	Errors []RecordError
+}
+
+// newBatchError returns a BatchError for the failures, or nil if there are none
+func newBatchError(failures []RecordError) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	return BatchError{Errors: failures}
+}
+
+func (b BatchError) Error() string {
+	if len(b.Errors) == 1 {
+		return b.Errors[0].Error()
+	}
+
+	messages := make([]string, 0, len(b.Errors))
+	for _, recordError := range b.Errors {
+		messages = append(messages, recordError.Error())
+	}
+	return fmt.Sprintf("%d records failed: %s", len(b.Errors), strings.Join(messages, "; "))
+}
+
+// Is reports whether any of the record errors matches the target
+func (b BatchError) Is(target error) bool {
+	for _, recordError := range b.Errors {
+		if errors.Is(recordError, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first record error that matches the target
+func (b BatchError) As(target any) bool {
+	for _, recordError := range b.Errors {
+		if errors.As(recordError, target) {
+			return true
+		}
+	}
+	return false
+}
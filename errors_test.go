@@ -0,0 +1,49 @@
+package hosttech
+
+import (
+	"context"
+	"errors"
+	"github.com/libdns/libdns"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestApiError_Is(t *testing.T) {
+	input := map[string]struct {
+		expectedResult error
+		data           ApiError
+	}{
+		"Unauthorized": {expectedResult: ErrUnauthorized, data: ApiError{ErrorCode: http.StatusUnauthorized}},
+		"Forbidden":    {expectedResult: ErrForbidden, data: ApiError{ErrorCode: http.StatusForbidden}},
+		"Not found":    {expectedResult: ErrNotFound, data: ApiError{ErrorCode: http.StatusNotFound}},
+		"Rate limited": {expectedResult: ErrRateLimited, data: ApiError{ErrorCode: http.StatusTooManyRequests}},
+		"Validation":   {expectedResult: ErrValidation, data: ApiError{ErrorCode: http.StatusUnprocessableEntity}},
+		"Server":       {expectedResult: ErrServer, data: ApiError{ErrorCode: http.StatusBadGateway}},
+	}
+
+	for name, testStruct := range input {
+		t.Run(name, func(t *testing.T) {
+			err := recordError("create", libdns.TXT{Name: "www"}, testStruct.data)
+
+			assert.ErrorIs(t, err, testStruct.expectedResult)
+			assert.NotErrorIs(t, err, ErrZoneNotFound)
+		})
+	}
+}
+
+func TestProvider_ZoneNotFound(t *testing.T) {
+	_, provider := newFakeHosttechAPI(t, "example.com")
+
+	_, err := provider.GetRecords(context.Background(), "example.org")
+
+	assert.ErrorIs(t, err, ErrZoneNotFound)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestUnsupportedRecordType(t *testing.T) {
+	_, err := LibdnsRecordToHosttechRecordWrapper(libdns.RR{Type: "SSHFP", Name: "host", Data: "1 1 abcdef"})
+
+	assert.True(t, errors.Is(err, ErrUnsupportedRecordType))
+	assert.Equal(t, `unsupported record type "SSHFP"`, err.Error())
+}
@@ -0,0 +1,183 @@
+package hosttech
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// defaultZoneCacheTTL is how long FindZone reuses the zones of the account, if Provider.ZoneCacheTTL is not set
+const defaultZoneCacheTTL = 5 * time.Minute
+
+// zoneCache holds the zones of the account for FindZone
+type zoneCache struct {
+	mutex     sync.Mutex
+	zones     []libdns.Zone
+	fetchedAt time.Time
+}
+
+// FindZone returns the name of the zone which contains the fully-qualified domain name. If several zones match, like
+// "example.co.uk" and "eu.example.co.uk", the longest one is chosen. The zones of the account are cached for
+// ZoneCacheTTL. If no cached zone matches, the zones are fetched again before an error matching ErrZoneNotFound
+// is returned.
+func (p *Provider) FindZone(ctx context.Context, fqdn string) (string, error) {
+	fqdn, err := toASCIIName(fqdn)
+	if err != nil {
+		return "", err
+	}
+
+	zone, err := p.findZone(ctx, fqdn)
+	if err != nil {
+		return "", err
+	}
+
+	return p.presentName(zone), nil
+}
+
+// findZone returns the name of the zone which contains the fully-qualified domain name in A-labels
+func (p *Provider) findZone(ctx context.Context, fqdn string) (string, error) {
+	zones, fresh, err := p.cachedZones(ctx, false)
+	if err != nil {
+		return "", err
+	}
+
+	zone, ok := longestMatchingZone(zones, fqdn)
+	if !ok && !fresh {
+		zones, _, err = p.cachedZones(ctx, true)
+		if err != nil {
+			return "", err
+		}
+		zone, ok = longestMatchingZone(zones, fqdn)
+	}
+	if !ok {
+		return "", fmt.Errorf(`%w: no zone contains "%s"`, ErrZoneNotFound, fqdn)
+	}
+
+	return zone, nil
+}
+
+// AppendRecordsFQDN works like AppendRecords, but the names of the records are fully-qualified domain names and every
+// record is added to the zone found by FindZone. The returned records carry fully-qualified names as well. They are
+// grouped by zone, in the order the zones first appear in the input.
+func (p *Provider) AppendRecordsFQDN(ctx context.Context, records []libdns.Record) ([]libdns.Record, error) {
+	return p.routeToZones(ctx, records, p.AppendRecords)
+}
+
+// SetRecordsFQDN works like SetRecords, but the names of the records are fully-qualified domain names and every
+// record is set in the zone found by FindZone. The returned records carry fully-qualified names as well. They are
+// grouped by zone, in the order the zones first appear in the input.
+func (p *Provider) SetRecordsFQDN(ctx context.Context, records []libdns.Record) ([]libdns.Record, error) {
+	return p.routeToZones(ctx, records, p.SetRecords)
+}
+
+// DeleteRecordsFQDN works like DeleteRecords, but the names of the records are fully-qualified domain names and every
+// record is deleted from the zone found by FindZone. The returned records carry fully-qualified names as well. They
+// are grouped by zone, in the order the zones first appear in the input.
+func (p *Provider) DeleteRecordsFQDN(ctx context.Context, records []libdns.Record) ([]libdns.Record, error) {
+	return p.routeToZones(ctx, records, p.DeleteRecords)
+}
+
+// routeToZones groups the records by their zone and calls the operation once per zone with relative names.
+// All zones are looked up before any change is made. As the operations may return more or fewer records than they
+// were given, the results can not be put back into the input order and stay grouped by zone.
+func (p *Provider) routeToZones(ctx context.Context, records []libdns.Record, operation func(context.Context, string, []libdns.Record) ([]libdns.Record, error)) ([]libdns.Record, error) {
+	var zoneOrder []string
+	recordsByZone := map[string][]libdns.Record{}
+	for _, record := range records {
+		record, err := toASCIIRecord(record)
+		if err != nil {
+			return nil, err
+		}
+		zone, err := p.findZone(ctx, record.RR().Name)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := recordsByZone[zone]; !ok {
+			zoneOrder = append(zoneOrder, zone)
+		}
+		recordsByZone[zone] = append(recordsByZone[zone], withName(record, func(name string) string {
+			relativeName, _ := relativeToZone(name, zone)
+			return relativeName
+		}))
+	}
+
+	var results []libdns.Record
+	var failures []RecordError
+	for _, zone := range zoneOrder {
+		zoneResults, err := operation(ctx, zone, recordsByZone[zone])
+		for _, record := range zoneResults {
+			results = append(results, withName(record, func(name string) string {
+				return libdns.AbsoluteName(name, p.presentName(zone)+".")
+			}))
+		}
+		if err == nil {
+			continue
+		}
+
+		var batchError BatchError
+		if !errors.As(err, &batchError) {
+			return results, err
+		}
+		failures = append(failures, batchError.Errors...)
+		if !p.continueOnError(ctx) {
+			break
+		}
+	}
+
+	return results, newBatchError(failures)
+}
+
+// cachedZones returns the zones of the account. They are fetched if refresh is set or the cache expired, which is
+// reported by fresh.
+func (p *Provider) cachedZones(ctx context.Context, refresh bool) (zones []libdns.Zone, fresh bool, err error) {
+	p.zoneCache.mutex.Lock()
+	defer p.zoneCache.mutex.Unlock()
+
+	ttl := p.ZoneCacheTTL
+	if ttl == 0 {
+		ttl = defaultZoneCacheTTL
+	}
+	if !refresh && p.zoneCache.zones != nil && time.Since(p.zoneCache.fetchedAt) < ttl {
+		return p.zoneCache.zones, false, nil
+	}
+
+	zones, err = p.listZones(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if zones == nil {
+		zones = []libdns.Zone{}
+	}
+	p.zoneCache.zones = zones
+	p.zoneCache.fetchedAt = time.Now()
+
+	return zones, true, nil
+}
+
+// invalidateZoneCache makes the next FindZone fetch the zones again
+func (p *Provider) invalidateZoneCache() {
+	p.zoneCache.mutex.Lock()
+	defer p.zoneCache.mutex.Unlock()
+
+	p.zoneCache.zones = nil
+}
+
+// longestMatchingZone returns the longest of the zones that is equal to or a parent of the fully-qualified name
+func longestMatchingZone(zones []libdns.Zone, fqdn string) (string, bool) {
+	name := strings.ToLower(RemoveTrailingDot(fqdn))
+
+	var match string
+	for _, zone := range zones {
+		zoneName := strings.ToLower(RemoveTrailingDot(zone.Name))
+		if (name == zoneName || strings.HasSuffix(name, "."+zoneName)) && len(zoneName) > len(match) {
+			match = zoneName
+		}
+	}
+
+	return match, match != ""
+}
@@ -0,0 +1,111 @@
+package hosttech
+
+import (
+	"context"
+	"github.com/libdns/libdns"
+	"github.com/stretchr/testify/assert"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestLongestMatchingZone(t *testing.T) {
+	zones := []libdns.Zone{{Name: "example.co.uk"}, {Name: "eu.example.co.uk"}, {Name: "example.com"}}
+
+	input := map[string]struct {
+		expectedResult string
+		expectedFound  bool
+		fqdn           string
+	}{
+		"Longest match": {
+			expectedResult: "eu.example.co.uk",
+			expectedFound:  true,
+			fqdn:           "_acme-challenge.api.eu.example.co.uk",
+		},
+		"Parent zone": {
+			expectedResult: "example.co.uk",
+			expectedFound:  true,
+			fqdn:           "www.example.co.uk.",
+		},
+		"Zone apex": {
+			expectedResult: "example.com",
+			expectedFound:  true,
+			fqdn:           "Example.com.",
+		},
+		"Label boundary": {
+			expectedFound: false,
+			fqdn:          "notexample.com",
+		},
+		"No zone": {
+			expectedFound: false,
+			fqdn:          "example.org",
+		},
+	}
+
+	for name, testStruct := range input {
+		t.Run(name, func(t *testing.T) {
+			output, found := longestMatchingZone(zones, testStruct.fqdn)
+
+			assert.Equal(t, testStruct.expectedFound, found)
+			assert.Equal(t, testStruct.expectedResult, output)
+		})
+	}
+}
+
+func TestProvider_FindZone(t *testing.T) {
+	api, provider := newFakeHosttechAPI(t, "example.co.uk")
+
+	zone, err := provider.FindZone(context.Background(), "www.example.co.uk")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.co.uk", zone)
+
+	// The new zone is not cached yet, so the zones are fetched again
+	api.zones = append(api.zones, HosttechZone{Id: 2, Name: "eu.example.co.uk"})
+	zone, err = provider.FindZone(context.Background(), "api.eu.example.co.uk")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.co.uk", zone)
+
+	_, err = provider.FindZone(context.Background(), "example.org")
+	assert.ErrorIs(t, err, ErrZoneNotFound)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	zone, err = provider.FindZone(context.Background(), "api.eu.example.co.uk")
+	assert.NoError(t, err)
+	assert.Equal(t, "eu.example.co.uk", zone)
+	assert.Equal(t, []string{"GET /api/user/v1/zones", "GET /api/user/v1/zones"}, api.requests)
+}
+
+func TestProvider_AppendRecordsFQDN(t *testing.T) {
+	api, provider := newFakeHosttechAPI(t, "example.co.uk")
+	api.zones = append(api.zones, HosttechZone{Id: 2, Name: "eu.example.co.uk"})
+	api.records["eu.example.co.uk"] = nil
+
+	records, err := provider.AppendRecordsFQDN(context.Background(), []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge.api.eu.example.co.uk.", Text: "token"},
+		libdns.Address{Name: "www.example.co.uk", IP: netip.MustParseAddr("192.0.2.1")},
+		libdns.TXT{Name: "eu.example.co.uk", Text: "apex"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge.api.eu.example.co.uk.", Text: "token", TTL: 600 * time.Second, ProviderData: 101},
+		libdns.TXT{Name: "eu.example.co.uk.", Text: "apex", TTL: 600 * time.Second, ProviderData: 102},
+		libdns.Address{Name: "www.example.co.uk.", IP: netip.MustParseAddr("192.0.2.1"), TTL: 600 * time.Second, ProviderData: 103},
+	}, records)
+	assert.Equal(t, "_acme-challenge.api", api.records["eu.example.co.uk"][0]["name"])
+	assert.Equal(t, "www", api.records["example.co.uk"][0]["name"])
+
+	// The zone is matched case-insensitively and only on whole labels
+	records, err = provider.AppendRecordsFQDN(context.Background(), []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge.WWW.Example.co.UK.", Text: "token"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge.WWW.example.co.uk.", Text: "token", TTL: 600 * time.Second, ProviderData: 104},
+	}, records)
+	assert.Equal(t, "_acme-challenge.WWW", api.records["example.co.uk"][1]["name"])
+
+	_, err = provider.AppendRecordsFQDN(context.Background(), []libdns.Record{libdns.TXT{Name: "example.org", Text: "token"}})
+	assert.ErrorIs(t, err, ErrZoneNotFound)
+}
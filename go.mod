@@ -3,12 +3,14 @@ module github.com/libdns/hosttech
 go 1.18
 
 require (
-	github.com/libdns/libdns v0.2.3
+	github.com/libdns/libdns v1.1.1
 	github.com/stretchr/testify v1.10.0
+	golang.org/x/net v0.35.0
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/text v0.22.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
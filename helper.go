@@ -1,7 +1,117 @@
 package hosttech
 
-import "strings"
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
 
 func RemoveTrailingDot(input string) string {
 	return strings.TrimRight(input, ".")
 }
+
+// parseLibdnsRecord returns the record as the typed libdns struct T. Records that are already of type T are returned
+// as they are, so their ProviderData is preserved. Any other record is parsed from its RR representation.
+func parseLibdnsRecord[T libdns.Record](record libdns.Record) (T, error) {
+	if typed, ok := record.(T); ok {
+		return typed, nil
+	}
+
+	var empty T
+	parsed, err := record.RR().Parse()
+	if err != nil {
+		return empty, err
+	}
+
+	typed, ok := parsed.(T)
+	if !ok {
+		return empty, fmt.Errorf(`record of type "%s" can not be converted to %T`, record.RR().Type, empty)
+	}
+
+	return typed, nil
+}
+
+// idFromProviderData extracts the Hosttech record id stored in the ProviderData of a libdns record.
+// If no id is present, 0 is returned.
+func idFromProviderData(providerData any) int {
+	id, ok := providerData.(int)
+	if !ok {
+		return 0
+	}
+	return id
+}
+
+// providerData returns the ProviderData of any of the typed libdns records. Records without ProviderData, like
+// libdns.RR, return nil.
+func providerData(record libdns.Record) any {
+	switch typed := record.(type) {
+	case libdns.Address:
+		return typed.ProviderData
+	case libdns.CAA:
+		return typed.ProviderData
+	case libdns.CNAME:
+		return typed.ProviderData
+	case libdns.MX:
+		return typed.ProviderData
+	case libdns.NS:
+		return typed.ProviderData
+	case libdns.SRV:
+		return typed.ProviderData
+	case libdns.ServiceBinding:
+		return typed.ProviderData
+	case libdns.TXT:
+		return typed.ProviderData
+	default:
+		return nil
+	}
+}
+
+// splitServiceName splits a relative SRV owner name like "_sip._tcp.sub" into the service "sip", the transport "tcp"
+// and the remaining name "sub". The name of services at the zone apex is "@". If the owner name does not start with
+// the two underscore labels, it is returned as the name with an empty service and transport.
+func splitServiceName(ownerName string) (service string, transport string, name string) {
+	labels := strings.SplitN(ownerName, ".", 3)
+	if len(labels) < 2 || !strings.HasPrefix(labels[0], "_") || !strings.HasPrefix(labels[1], "_") {
+		return "", "", ownerName
+	}
+
+	name = "@"
+	if len(labels) == 3 {
+		name = labels[2]
+	}
+
+	return strings.TrimPrefix(labels[0], "_"), strings.TrimPrefix(labels[1], "_"), name
+}
+
+// generateRequestMarker returns a random marker that identifies a single create request in the comment of a record
+func generateRequestMarker() (string, error) {
+	marker := make([]byte, 8)
+	if _, err := rand.Read(marker); err != nil {
+		return "", err
+	}
+	return "libdns-request-" + hex.EncodeToString(marker), nil
+}
+
+// marshalWithComment marshals the record and replaces its comment. This works for any HosttechRecord implementation,
+// as long as its comment is stored in the "comment" field like in Base.
+func marshalWithComment(record HosttechRecord, comment string) ([]byte, error) {
+	bodyBytes, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(bodyBytes, &fields); err != nil {
+		return nil, err
+	}
+	fields["comment"], err = json.Marshal(comment)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(fields)
+}
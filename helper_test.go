@@ -28,3 +28,31 @@ func TestRemoveTrailingDot(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitServiceName(t *testing.T) {
+	input := map[string]struct {
+		expectedResult [3]string
+		data           string
+	}{
+		"Service with name": {
+			expectedResult: [3]string{"sip", "tcp", "sub"},
+			data:           "_sip._tcp.sub",
+		},
+		"Service at apex": {
+			expectedResult: [3]string{"xmpp-server", "tcp", "@"},
+			data:           "_xmpp-server._tcp",
+		},
+		"Name without service": {
+			expectedResult: [3]string{"", "", "sub"},
+			data:           "sub",
+		},
+	}
+
+	for name, testStruct := range input {
+		t.Run(name, func(t *testing.T) {
+			service, transport, ownerName := splitServiceName(testStruct.data)
+
+			assert.Equal(t, testStruct.expectedResult, [3]string{service, transport, ownerName})
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package hosttech
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/libdns/libdns"
+	"golang.org/x/net/idna"
+)
+
+// Internationalised domain names are sent to Hosttech in their ASCII form (A-labels like "xn--bcher-kva"). Labels which
+// are already ASCII are passed on unchanged, so service labels like "_sip", wildcards and "@" keep working.
+
+// aLabelPrefix marks a label in its ASCII compatible encoding
+const aLabelPrefix = "xn--"
+
+// toASCIIName converts every label of the domain name to its A-label, e.g. "www.bücher.ch" to "www.xn--bcher-kva.ch".
+// Invalid labels result in an error matching ErrValidation.
+func toASCIIName(name string) (string, error) {
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if isASCII(label) {
+			if !strings.HasPrefix(strings.ToLower(label), aLabelPrefix) {
+				continue
+			}
+			// A-labels have to decode to a valid U-label
+			if _, err := idna.Lookup.ToUnicode(label); err != nil {
+				return "", fmt.Errorf(`%w: label "%s" of "%s" is not a valid A-label: %v`, ErrValidation, label, name, err)
+			}
+			continue
+		}
+
+		aLabel, err := idna.Lookup.ToASCII(label)
+		if err != nil {
+			return "", fmt.Errorf(`%w: label "%s" of "%s" is not a valid internationalised domain name: %v`, ErrValidation, label, name, err)
+		}
+		labels[i] = aLabel
+	}
+
+	return strings.Join(labels, "."), nil
+}
+
+// toUnicodeName converts every A-label of the domain name to its U-label, e.g. "www.xn--bcher-kva.ch" to
+// "www.bücher.ch". Labels which can not be converted are kept as they are.
+func toUnicodeName(name string) string {
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if !strings.HasPrefix(strings.ToLower(label), aLabelPrefix) {
+			continue
+		}
+		if uLabel, err := idna.Lookup.ToUnicode(label); err == nil {
+			labels[i] = uLabel
+		}
+	}
+
+	return strings.Join(labels, ".")
+}
+
+// toASCIIRecord converts the owner name and the target of the record to A-labels
+func toASCIIRecord(record libdns.Record) (libdns.Record, error) {
+	var err error
+	convert := func(name string) string {
+		aName, convertErr := toASCIIName(name)
+		if convertErr != nil && err == nil {
+			err = convertErr
+		}
+		return aName
+	}
+
+	record = withTarget(withName(record, convert), convert)
+
+	return record, err
+}
+
+// toUnicodeRecord converts the owner name and the target of the record to U-labels
+func toUnicodeRecord(record libdns.Record) libdns.Record {
+	return withTarget(withName(record, toUnicodeName), toUnicodeName)
+}
+
+// presentRecords converts the names of the returned records to U-labels, if UnicodeNames is set
+func (p *Provider) presentRecords(records []libdns.Record) []libdns.Record {
+	if !p.UnicodeNames {
+		return records
+	}
+
+	for i, record := range records {
+		records[i] = toUnicodeRecord(record)
+	}
+	return records
+}
+
+// presentName converts a returned domain name to U-labels, if UnicodeNames is set
+func (p *Provider) presentName(name string) string {
+	if !p.UnicodeNames {
+		return name
+	}
+	return toUnicodeName(name)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
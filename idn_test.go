@@ -0,0 +1,104 @@
+package hosttech
+
+import (
+	"context"
+	"github.com/libdns/libdns"
+	"github.com/stretchr/testify/assert"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestToASCIIName(t *testing.T) {
+	input := map[string]struct {
+		expectedResult string
+		expectedError  bool
+		name           string
+	}{
+		"ASCII name": {
+			expectedResult: "_acme-challenge.www.example.ch.",
+			name:           "_acme-challenge.www.example.ch.",
+		},
+		"Unicode name": {
+			expectedResult: "www.xn--bcher-kva.ch",
+			name:           "www.bücher.ch",
+		},
+		"Unicode name with uppercase letters": {
+			expectedResult: "xn--bcher-kva.ch",
+			name:           "BÜCHER.ch",
+		},
+		"A-label": {
+			expectedResult: "xn--bcher-kva.ch",
+			name:           "xn--bcher-kva.ch",
+		},
+		"Wildcard": {
+			expectedResult: "*.xn--bcher-kva",
+			name:           "*.bücher",
+		},
+		"Apex": {
+			expectedResult: "@",
+			name:           "@",
+		},
+		"Invalid U-label starting with a combining mark": {
+			expectedError: true,
+			name:          "\u0300bücher.ch",
+		},
+		"Invalid A-label": {
+			expectedError: true,
+			name:          "xn--zz.ch",
+		},
+	}
+
+	for name, testStruct := range input {
+		t.Run(name, func(t *testing.T) {
+			output, err := toASCIIName(testStruct.name)
+
+			if testStruct.expectedError {
+				assert.ErrorIs(t, err, ErrValidation)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testStruct.expectedResult, output)
+		})
+	}
+}
+
+func TestToUnicodeName(t *testing.T) {
+	assert.Equal(t, "www.bücher.ch.", toUnicodeName("www.xn--bcher-kva.ch."))
+	assert.Equal(t, "_sip._tcp.example.ch", toUnicodeName("_sip._tcp.example.ch"))
+	assert.Equal(t, "xn--zz.ch", toUnicodeName("xn--zz.ch"))
+}
+
+func TestProvider_IDN(t *testing.T) {
+	api, provider := newFakeHosttechAPI(t, "xn--bcher-kva.ch")
+
+	appended, err := provider.AppendRecords(context.Background(), "bücher.ch", []libdns.Record{
+		libdns.CNAME{Name: "läden.bücher.ch.", Target: "shop.bücher.ch."},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []libdns.Record{libdns.CNAME{Name: "xn--lden-loa", Target: "shop.xn--bcher-kva.ch", TTL: 600 * time.Second, ProviderData: 101}}, appended)
+	assert.Equal(t, "POST /api/user/v1/zones/xn--bcher-kva.ch/records", api.requests[0])
+	assert.Equal(t, "xn--lden-loa", api.records["xn--bcher-kva.ch"][0]["name"])
+
+	provider.UnicodeNames = true
+	records, err := provider.GetRecords(context.Background(), "xn--bcher-kva.ch")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []libdns.Record{libdns.CNAME{Name: "läden", Target: "shop.bücher.ch", TTL: 600 * time.Second, ProviderData: 101}}, records)
+
+	zones, err := provider.ListZones(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []libdns.Zone{{Name: "bücher.ch"}}, zones)
+
+	zone, err := provider.FindZone(context.Background(), "www.BÜCHER.ch")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "bücher.ch", zone)
+
+	_, err = provider.AppendRecords(context.Background(), "bücher.ch", []libdns.Record{
+		libdns.Address{Name: "\u0300b", IP: netip.MustParseAddr("192.0.2.1")},
+	})
+	assert.ErrorIs(t, err, ErrValidation)
+}
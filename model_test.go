@@ -3,6 +3,7 @@ package hosttech
 import (
 	"github.com/libdns/libdns"
 	"github.com/stretchr/testify/assert"
+	"net/netip"
 	"testing"
 	"time"
 )
@@ -42,13 +43,11 @@ func TestHosttechRecordToLibdnsRecord(t *testing.T) {
 		data           HosttechRecord
 	}{
 		"ARecord Test": {
-			expectedResult: libdns.Record{
-				ID:       "14",
-				Type:     "A",
-				Name:     "sub",
-				Value:    "192.168.68.1",
-				TTL:      1800 * time.Second,
-				Priority: 0,
+			expectedResult: libdns.Address{
+				Name:         "sub",
+				IP:           netip.MustParseAddr("192.168.68.1"),
+				TTL:          1800 * time.Second,
+				ProviderData: 14,
 			},
 			data: ARecord{
 				Base: Base{
@@ -62,13 +61,11 @@ func TestHosttechRecordToLibdnsRecord(t *testing.T) {
 			},
 		},
 		"AAAARecord Test": {
-			expectedResult: libdns.Record{
-				ID:       "23",
-				Type:     "AAAA",
-				Name:     "sub",
-				Value:    "2607:f0d0:1002:51::4",
-				TTL:      1800 * time.Second,
-				Priority: 0,
+			expectedResult: libdns.Address{
+				Name:         "sub",
+				IP:           netip.MustParseAddr("2607:f0d0:1002:51::4"),
+				TTL:          1800 * time.Second,
+				ProviderData: 23,
 			},
 			data: AAAARecord{
 				Base: Base{
@@ -82,13 +79,11 @@ func TestHosttechRecordToLibdnsRecord(t *testing.T) {
 			},
 		},
 		"NSRecord Test": {
-			expectedResult: libdns.Record{
-				ID:       "12",
-				Type:     "NS",
-				Name:     "sub",
-				Value:    "ns1.example.com",
-				TTL:      1900 * time.Second,
-				Priority: 0,
+			expectedResult: libdns.NS{
+				Name:         "sub",
+				Target:       "ns1.example.com",
+				TTL:          1900 * time.Second,
+				ProviderData: 12,
 			},
 			data: NSRecord{
 				Base: Base{
@@ -102,13 +97,11 @@ func TestHosttechRecordToLibdnsRecord(t *testing.T) {
 			},
 		},
 		"CNAMERecord Test": {
-			expectedResult: libdns.Record{
-				ID:       "143",
-				Type:     "CNAME",
-				Name:     "sub",
-				Value:    "site.example.com",
-				TTL:      1700 * time.Second,
-				Priority: 0,
+			expectedResult: libdns.CNAME{
+				Name:         "sub",
+				Target:       "site.example.com",
+				TTL:          1700 * time.Second,
+				ProviderData: 143,
 			},
 			data: CNAMERecord{
 				Base: Base{
@@ -122,13 +115,12 @@ func TestHosttechRecordToLibdnsRecord(t *testing.T) {
 			},
 		},
 		"MXRecord Test": {
-			expectedResult: libdns.Record{
-				ID:       "748",
-				Type:     "MX",
-				Name:     "sub",
-				Value:    "mail.server.com",
-				TTL:      1750 * time.Second,
-				Priority: 10,
+			expectedResult: libdns.MX{
+				Name:         "sub",
+				Target:       "mail.server.com",
+				Preference:   10,
+				TTL:          1750 * time.Second,
+				ProviderData: 748,
 			},
 			data: MXRecord{
 				Base: Base{
@@ -143,13 +135,11 @@ func TestHosttechRecordToLibdnsRecord(t *testing.T) {
 			},
 		},
 		"TXTRecord Test": {
-			expectedResult: libdns.Record{
-				ID:       "178",
-				Type:     "TXT",
-				Name:     "sub",
-				Value:    "Some cool text",
-				TTL:      1690 * time.Second,
-				Priority: 0,
+			expectedResult: libdns.TXT{
+				Name:         "sub",
+				Text:         "Some cool text",
+				TTL:          1690 * time.Second,
+				ProviderData: 178,
 			},
 			data: TXTRecord{
 				Base: Base{
@@ -162,14 +152,60 @@ func TestHosttechRecordToLibdnsRecord(t *testing.T) {
 				Text: "Some cool text",
 			},
 		},
+		"SRVRecord Test": {
+			expectedResult: libdns.SRV{
+				Service:      "sip",
+				Transport:    "tcp",
+				Name:         "sub",
+				Priority:     10,
+				Weight:       20,
+				Port:         5060,
+				Target:       "sip.example.com",
+				TTL:          3600 * time.Second,
+				ProviderData: 52,
+			},
+			data: SRVRecord{
+				Base: Base{
+					Id:      52,
+					Type:    "SRV",
+					TTL:     3600,
+					Comment: "Some comment",
+				},
+				Service:  "_sip._tcp.sub.example.com",
+				Priority: 10,
+				Weight:   20,
+				Port:     5060,
+				Target:   "sip.example.com",
+			},
+		},
+		"CAARecord Test": {
+			expectedResult: libdns.CAA{
+				Name:         "@",
+				Flags:        0,
+				Tag:          "issue",
+				Value:        "letsencrypt.org",
+				TTL:          3600 * time.Second,
+				ProviderData: 53,
+			},
+			data: CAARecord{
+				Base: Base{
+					Id:      53,
+					Type:    "CAA",
+					TTL:     3600,
+					Comment: "Some comment",
+				},
+				Name:  "example.com",
+				Flag:  0,
+				Tag:   "issue",
+				Value: "letsencrypt.org",
+			},
+		},
 		"TLSARecord Test": {
-			expectedResult: libdns.Record{
-				ID:       "61",
-				Type:     "TLSA",
-				Name:     "sub",
-				Value:    "TLSA text",
-				TTL:      1700 * time.Second,
-				Priority: 0,
+			expectedResult: libdns.RR{
+				Type: "TLSA",
+				Name: "sub",
+				Data: "3 1 1 d2abde240d7cd3ee6b4b28c54df034b97983a1d16e8a410e4561cb106618e971",
+				TTL:  1700 * time.Second,
 			},
 			data: TLSARecord{
 				Base: Base{
@@ -179,14 +215,19 @@ func TestHosttechRecordToLibdnsRecord(t *testing.T) {
 					Comment: "Some comment",
 				},
 				Name: "sub.example.com",
-				Text: "TLSA text",
+				TLSA: TLSA{
+					Usage:           3,
+					Selector:        1,
+					MatchingType:    1,
+					CertificateData: "d2abde240d7cd3ee6b4b28c54df034b97983a1d16e8a410e4561cb106618e971",
+				},
 			},
 		},
 	}
 
 	for name, testStruct := range input {
 		t.Run(name, func(t *testing.T) {
-			output := testStruct.data.toLibdnsRecord(zone)
+			output := testStruct.data.ToLibdnsRecord(zone)
 
 			assert.Equal(t, testStruct.expectedResult, output)
 		})
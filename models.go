@@ -1,16 +1,21 @@
 package hosttech
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
-	"github.com/libdns/libdns"
-	"strconv"
+	"net/netip"
+	"strings"
 	"time"
+
+	"github.com/libdns/libdns"
 )
 
 // HosttechRecord must be implemented by each different type of record representation from the Hosttech.ch API, to allow a transformation from and to libdns.record.
 type HosttechRecord interface {
-	toLibdnsRecord(zone string) libdns.Record
-	fromLibdnsRecord(record libdns.Record) HosttechRecord
+	ToLibdnsRecord(zone string) libdns.Record
+	FromLibdnsRecord(record libdns.Record) (HosttechRecord, error)
+	base() Base
 }
 
 // Base holds all the values that are present in each record
@@ -21,6 +26,10 @@ type Base struct {
 	Comment string `json:"comment,omitempty"`
 }
 
+func (b Base) base() Base {
+	return b
+}
+
 // AAAARecord is an implementation of the AAAA record type
 type AAAARecord struct {
 	Base
@@ -28,24 +37,42 @@ type AAAARecord struct {
 	IPV6 string `json:"ipv6,omitempty"`
 }
 
-func (a AAAARecord) toLibdnsRecord(zone string) libdns.Record {
-	return libdns.Record{
-		ID:    strconv.Itoa(a.Id),
-		Type:  a.Type,
-		Name:  libdns.RelativeName(a.Name, zone),
-		Value: a.IPV6,
-		TTL:   time.Duration(a.TTL * 1000000000),
+func (a AAAARecord) ToLibdnsRecord(zone string) libdns.Record {
+	ip, err := netip.ParseAddr(a.IPV6)
+	if err != nil {
+		return libdns.RR{
+			Type: "AAAA",
+			Name: libdnsOwnerName(a.Name, zone),
+			Data: a.IPV6,
+			TTL:  intSecondsToDuration(a.TTL),
+		}
+	}
+
+	return libdns.Address{
+		Name:         libdnsOwnerName(a.Name, zone),
+		IP:           ip,
+		TTL:          intSecondsToDuration(a.TTL),
+		ProviderData: a.Id,
 	}
 }
 
-func (a AAAARecord) fromLibdnsRecord(record libdns.Record) HosttechRecord {
-	a.Name = record.Name
-	a.Type = record.Type
-	a.IPV6 = record.Value
-	a.TTL = durationToIntSeconds(record.TTL)
+func (a AAAARecord) FromLibdnsRecord(record libdns.Record) (HosttechRecord, error) {
+	address, err := parseLibdnsRecord[libdns.Address](record)
+	if err != nil {
+		return nil, err
+	}
+	if !address.IP.Is6() {
+		return nil, fmt.Errorf(`"%s" is not a valid IPv6 address`, address.IP)
+	}
+
+	a.Id = idFromProviderData(address.ProviderData)
+	a.Name = hosttechOwnerName(address.Name)
+	a.Type = "AAAA"
+	a.IPV6 = address.IP.String()
+	a.TTL = durationToIntSeconds(address.TTL)
 	a.Comment = generateComment()
 
-	return a
+	return a, nil
 }
 
 // ARecord is an implementation of the A record type
@@ -55,24 +82,42 @@ type ARecord struct {
 	IPV4 string `json:"ipv4,omitempty"`
 }
 
-func (a ARecord) toLibdnsRecord(zone string) libdns.Record {
-	return libdns.Record{
-		ID:    strconv.Itoa(a.Id),
-		Type:  a.Type,
-		Name:  libdns.RelativeName(a.Name, zone),
-		Value: a.IPV4,
-		TTL:   time.Duration(a.TTL * 1000000000),
+func (a ARecord) ToLibdnsRecord(zone string) libdns.Record {
+	ip, err := netip.ParseAddr(a.IPV4)
+	if err != nil {
+		return libdns.RR{
+			Type: "A",
+			Name: libdnsOwnerName(a.Name, zone),
+			Data: a.IPV4,
+			TTL:  intSecondsToDuration(a.TTL),
+		}
+	}
+
+	return libdns.Address{
+		Name:         libdnsOwnerName(a.Name, zone),
+		IP:           ip,
+		TTL:          intSecondsToDuration(a.TTL),
+		ProviderData: a.Id,
 	}
 }
 
-func (a ARecord) fromLibdnsRecord(record libdns.Record) HosttechRecord {
-	a.Name = record.Name
-	a.Type = record.Type
-	a.IPV4 = record.Value
-	a.TTL = durationToIntSeconds(record.TTL)
+func (a ARecord) FromLibdnsRecord(record libdns.Record) (HosttechRecord, error) {
+	address, err := parseLibdnsRecord[libdns.Address](record)
+	if err != nil {
+		return nil, err
+	}
+	if !address.IP.Is4() {
+		return nil, fmt.Errorf(`"%s" is not a valid IPv4 address`, address.IP)
+	}
+
+	a.Id = idFromProviderData(address.ProviderData)
+	a.Name = hosttechOwnerName(address.Name)
+	a.Type = "A"
+	a.IPV4 = address.IP.String()
+	a.TTL = durationToIntSeconds(address.TTL)
 	a.Comment = generateComment()
 
-	return a
+	return a, nil
 }
 
 // CNAMERecord is an implementation of the CNAME record type
@@ -82,24 +127,29 @@ type CNAMERecord struct {
 	Cname string `json:"cname,omitempty"`
 }
 
-func (c CNAMERecord) toLibdnsRecord(zone string) libdns.Record {
-	return libdns.Record{
-		ID:    strconv.Itoa(c.Id),
-		Type:  c.Type,
-		Name:  libdns.RelativeName(c.Name, zone),
-		Value: c.Cname,
-		TTL:   time.Duration(c.TTL * 1000000000),
+func (c CNAMERecord) ToLibdnsRecord(zone string) libdns.Record {
+	return libdns.CNAME{
+		Name:         libdnsOwnerName(c.Name, zone),
+		Target:       normalizeTarget(c.Cname),
+		TTL:          intSecondsToDuration(c.TTL),
+		ProviderData: c.Id,
 	}
 }
 
-func (c CNAMERecord) fromLibdnsRecord(record libdns.Record) HosttechRecord {
-	c.Name = record.Name
-	c.Type = record.Type
-	c.Cname = record.Value
-	c.TTL = durationToIntSeconds(record.TTL)
+func (c CNAMERecord) FromLibdnsRecord(record libdns.Record) (HosttechRecord, error) {
+	cname, err := parseLibdnsRecord[libdns.CNAME](record)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Id = idFromProviderData(cname.ProviderData)
+	c.Name = hosttechOwnerName(cname.Name)
+	c.Type = "CNAME"
+	c.Cname = normalizeTarget(cname.Target)
+	c.TTL = durationToIntSeconds(cname.TTL)
 	c.Comment = generateComment()
 
-	return c
+	return c, nil
 }
 
 // MXRecord is an implementation of the MX record type
@@ -107,29 +157,34 @@ type MXRecord struct {
 	Base
 	Name      string `json:"name,omitempty"`
 	OwnerName string `json:"ownername,omitempty"`
-	Pref      uint   `json:"pref,omitempty"`
+	Pref      uint16 `json:"pref,omitempty"`
 }
 
-func (m MXRecord) toLibdnsRecord(zone string) libdns.Record {
-	return libdns.Record{
-		ID:       strconv.Itoa(m.Id),
-		Type:     m.Type,
-		Name:     libdns.RelativeName(m.OwnerName, zone),
-		Value:    m.Name,
-		TTL:      time.Duration(m.TTL * 1000000000),
-		Priority: m.Pref,
+func (m MXRecord) ToLibdnsRecord(zone string) libdns.Record {
+	return libdns.MX{
+		Name:         libdnsOwnerName(m.OwnerName, zone),
+		Target:       normalizeTarget(m.Name),
+		Preference:   m.Pref,
+		TTL:          intSecondsToDuration(m.TTL),
+		ProviderData: m.Id,
 	}
 }
 
-func (m MXRecord) fromLibdnsRecord(record libdns.Record) HosttechRecord {
-	m.OwnerName = record.Name
-	m.Type = record.Type
-	m.TTL = durationToIntSeconds(record.TTL)
-	m.Name = record.Value
-	m.Pref = record.Priority
+func (m MXRecord) FromLibdnsRecord(record libdns.Record) (HosttechRecord, error) {
+	mx, err := parseLibdnsRecord[libdns.MX](record)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Id = idFromProviderData(mx.ProviderData)
+	m.OwnerName = hosttechOwnerName(mx.Name)
+	m.Type = "MX"
+	m.TTL = durationToIntSeconds(mx.TTL)
+	m.Name = normalizeTarget(mx.Target)
+	m.Pref = mx.Preference
 	m.Comment = generateComment()
 
-	return m
+	return m, nil
 }
 
 // NSRecord is an implementation of the NS record type
@@ -139,24 +194,29 @@ type NSRecord struct {
 	TargetName string `json:"targetname,omitempty"`
 }
 
-func (n NSRecord) toLibdnsRecord(zone string) libdns.Record {
-	return libdns.Record{
-		ID:    strconv.Itoa(n.Id),
-		Type:  n.Type,
-		Name:  libdns.RelativeName(n.OwnerName, zone),
-		Value: n.TargetName,
-		TTL:   time.Duration(n.TTL * 1000000000),
+func (n NSRecord) ToLibdnsRecord(zone string) libdns.Record {
+	return libdns.NS{
+		Name:         libdnsOwnerName(n.OwnerName, zone),
+		Target:       normalizeTarget(n.TargetName),
+		TTL:          intSecondsToDuration(n.TTL),
+		ProviderData: n.Id,
 	}
 }
 
-func (n NSRecord) fromLibdnsRecord(record libdns.Record) HosttechRecord {
-	n.OwnerName = record.Name
-	n.Type = record.Type
-	n.TargetName = record.Value
-	n.TTL = durationToIntSeconds(record.TTL)
+func (n NSRecord) FromLibdnsRecord(record libdns.Record) (HosttechRecord, error) {
+	ns, err := parseLibdnsRecord[libdns.NS](record)
+	if err != nil {
+		return nil, err
+	}
+
+	n.Id = idFromProviderData(ns.ProviderData)
+	n.OwnerName = hosttechOwnerName(ns.Name)
+	n.Type = "NS"
+	n.TargetName = normalizeTarget(ns.Target)
+	n.TTL = durationToIntSeconds(ns.TTL)
 	n.Comment = generateComment()
 
-	return n
+	return n, nil
 }
 
 // TXTRecord is an implementation of the TXT record type
@@ -166,33 +226,293 @@ type TXTRecord struct {
 	Text string `json:"text,omitempty"`
 }
 
-func (t TXTRecord) toLibdnsRecord(zone string) libdns.Record {
-	return libdns.Record{
-		ID:    strconv.Itoa(t.Id),
-		Type:  t.Type,
-		Name:  libdns.RelativeName(t.Name, zone),
-		Value: t.Text,
-		TTL:   time.Duration(t.TTL * 1000000000),
+func (t TXTRecord) ToLibdnsRecord(zone string) libdns.Record {
+	return libdns.TXT{
+		Name:         libdnsOwnerName(t.Name, zone),
+		Text:         t.Text,
+		TTL:          intSecondsToDuration(t.TTL),
+		ProviderData: t.Id,
 	}
 }
 
-func (t TXTRecord) fromLibdnsRecord(record libdns.Record) HosttechRecord {
-	t.Name = RemoveTrailingDot(record.Name)
-	t.Type = record.Type
-	t.Text = record.Value
-	t.TTL = durationToIntSeconds(record.TTL)
+func (t TXTRecord) FromLibdnsRecord(record libdns.Record) (HosttechRecord, error) {
+	txt, err := parseLibdnsRecord[libdns.TXT](record)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Id = idFromProviderData(txt.ProviderData)
+	t.Name = hosttechOwnerName(txt.Name)
+	t.Type = "TXT"
+	t.Text = txt.Text
+	t.TTL = durationToIntSeconds(txt.TTL)
 	t.Comment = generateComment()
 
-	return t
+	return t, nil
+}
+
+// SRVRecord is an implementation of the SRV record type.
+// The service holds the owner name including the service and protocol labels, e.g. "_sip._tcp" or "_sip._tcp.sub".
+type SRVRecord struct {
+	Base
+	Service  string `json:"service,omitempty"`
+	Priority uint16 `json:"priority"`
+	Weight   uint16 `json:"weight"`
+	Port     uint16 `json:"port"`
+	Target   string `json:"target,omitempty"`
+}
+
+func (s SRVRecord) ToLibdnsRecord(zone string) libdns.Record {
+	service, transport, name := splitServiceName(libdnsOwnerName(s.Service, zone))
+
+	return libdns.SRV{
+		Service:      service,
+		Transport:    transport,
+		Name:         name,
+		Priority:     s.Priority,
+		Weight:       s.Weight,
+		Port:         s.Port,
+		Target:       normalizeTarget(s.Target),
+		TTL:          intSecondsToDuration(s.TTL),
+		ProviderData: s.Id,
+	}
+}
+
+func (s SRVRecord) FromLibdnsRecord(record libdns.Record) (HosttechRecord, error) {
+	srv, err := parseLibdnsRecord[libdns.SRV](record)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Id = idFromProviderData(srv.ProviderData)
+	s.Service = hosttechOwnerName(srv.RR().Name)
+	s.Type = "SRV"
+	s.Priority = srv.Priority
+	s.Weight = srv.Weight
+	s.Port = srv.Port
+	s.Target = normalizeTarget(srv.Target)
+	s.TTL = durationToIntSeconds(srv.TTL)
+	s.Comment = generateComment()
+
+	return s, nil
+}
+
+// CAARecord is an implementation of the CAA record type
+type CAARecord struct {
+	Base
+	Name  string `json:"name,omitempty"`
+	Flag  uint8  `json:"flag"`
+	Tag   string `json:"tag,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// The CAA property tags that can be set with the Hosttech API
+var caaTags = map[string]bool{
+	"issue":     true,
+	"issuewild": true,
+	"iodef":     true,
+}
+
+func (c CAARecord) ToLibdnsRecord(zone string) libdns.Record {
+	return libdns.CAA{
+		Name:         libdnsOwnerName(c.Name, zone),
+		Flags:        c.Flag,
+		Tag:          c.Tag,
+		Value:        c.Value,
+		TTL:          intSecondsToDuration(c.TTL),
+		ProviderData: c.Id,
+	}
+}
+
+func (c CAARecord) FromLibdnsRecord(record libdns.Record) (HosttechRecord, error) {
+	caa, err := parseLibdnsRecord[libdns.CAA](record)
+	if err != nil {
+		return nil, err
+	}
+	if !caaTags[caa.Tag] {
+		return nil, fmt.Errorf(`%w: CAA tag "%s" is not supported, it has to be one of issue, issuewild or iodef`, ErrValidation, caa.Tag)
+	}
+
+	c.Id = idFromProviderData(caa.ProviderData)
+	c.Name = hosttechOwnerName(caa.Name)
+	c.Type = "CAA"
+	c.Flag = caa.Flags
+	c.Tag = caa.Tag
+	c.Value = caa.Value
+	c.TTL = durationToIntSeconds(caa.TTL)
+	c.Comment = generateComment()
+
+	return c, nil
+}
+
+// PTRRecord is an implementation of the PTR record type.
+// The origin holds the owner name inside the reverse zone, the name holds the host name the address points to.
+type PTRRecord struct {
+	Base
+	Origin string `json:"origin,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+func (p PTRRecord) ToLibdnsRecord(zone string) libdns.Record {
+	return libdns.RR{
+		Type: "PTR",
+		Name: libdnsOwnerName(p.Origin, zone),
+		Data: normalizeTarget(p.Name),
+		TTL:  intSecondsToDuration(p.TTL),
+	}
+}
+
+func (p PTRRecord) FromLibdnsRecord(record libdns.Record) (HosttechRecord, error) {
+	rr := record.RR()
+
+	p.Origin = hosttechOwnerName(rr.Name)
+	p.Type = "PTR"
+	p.Name = normalizeTarget(rr.Data)
+	p.TTL = durationToIntSeconds(rr.TTL)
+	p.Comment = generateComment()
+
+	return p, nil
 }
 
-// TLSARecord is an implementation of the TLSA record type
+// TLSARecord is an implementation of the TLSA record type.
+// libdns has no dedicated TLSA type, so it is represented as a plain libdns.RR.
+// The Hosttech API transfers the record data in its presentation form, which is parsed into the embedded TLSA.
 type TLSARecord struct {
+	Base
+	Name string `json:"name,omitempty"`
+	TLSA `json:"-"`
+}
+
+// tlsaRecordJSON is the representation of a TLSARecord in the Hosttech API
+type tlsaRecordJSON struct {
 	Base
 	Name string `json:"name,omitempty"`
 	Text string `json:"text,omitempty"`
 }
 
+func (t TLSARecord) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tlsaRecordJSON{
+		Base: t.Base,
+		Name: t.Name,
+		Text: t.TLSA.String(),
+	})
+}
+
+func (t *TLSARecord) UnmarshalJSON(b []byte) error {
+	var record tlsaRecordJSON
+	err := json.Unmarshal(b, &record)
+	if err != nil {
+		return err
+	}
+
+	tlsa, err := ParseTLSA(record.Text)
+	if err != nil {
+		return err
+	}
+
+	t.Base = record.Base
+	t.Name = record.Name
+	t.TLSA = tlsa
+
+	return nil
+}
+
+// UnknownRecord is the fallback for records of types that are not modelled by this package, or that could not be decoded.
+// It keeps the raw JSON returned by the Hosttech API and is only ever read: it can not be created or updated, but it can
+// be deleted.
+type UnknownRecord struct {
+	Base
+	Name string
+	// Value is a best-effort presentation of all record specific fields
+	Value string
+	Raw   json.RawMessage
+}
+
+// The keys the Hosttech API uses for the owner name of a record, in the order they are looked up
+var unknownRecordNameKeys = []string{"name", "ownername", "origin", "service"}
+
+func newUnknownRecord(base Base, raw []byte) UnknownRecord {
+	record := UnknownRecord{
+		Base: base,
+		Raw:  append(json.RawMessage{}, raw...),
+	}
+
+	keys, fields, err := decodeOrderedFields(raw)
+	if err != nil {
+		return record
+	}
+
+	for _, key := range unknownRecordNameKeys {
+		var name string
+		if json.Unmarshal(fields[key], &name) == nil {
+			record.Name = name
+			delete(fields, key)
+			break
+		}
+	}
+
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		field, ok := fields[key]
+		if !ok || key == "id" || key == "type" || key == "ttl" || key == "comment" {
+			continue
+		}
+
+		var text string
+		switch {
+		case string(field) == "null":
+			continue
+		case json.Unmarshal(field, &text) == nil:
+			values = append(values, text)
+		default:
+			values = append(values, string(field))
+		}
+	}
+	record.Value = strings.Join(values, " ")
+
+	return record
+}
+
+// decodeOrderedFields decodes a JSON object into its raw fields, keeping the order of the keys
+func decodeOrderedFields(raw []byte) ([]string, map[string]json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := decoder.Token(); err != nil {
+		return nil, nil, err
+	}
+
+	var keys []string
+	fields := map[string]json.RawMessage{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key, _ := token.(string)
+
+		var field json.RawMessage
+		if err := decoder.Decode(&field); err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, key)
+		fields[key] = field
+	}
+
+	return keys, fields, nil
+}
+
+func (u UnknownRecord) ToLibdnsRecord(zone string) libdns.Record {
+	return libdns.RR{
+		Type: u.Type,
+		Name: libdnsOwnerName(u.Name, zone),
+		Data: u.Value,
+		TTL:  intSecondsToDuration(u.TTL),
+	}
+}
+
+func (u UnknownRecord) FromLibdnsRecord(record libdns.Record) (HosttechRecord, error) {
+	return nil, fmt.Errorf(`%w "%s"`, ErrUnsupportedRecordType, record.RR().Type)
+}
+
 // HosttechZone is an implementation of the zone without records
 type HosttechZone struct {
 	Id          uint   `json:"id,omitempty"`
@@ -204,30 +524,49 @@ type HosttechZone struct {
 	DNSSECEmail string `json:"dnssec_email,omitempty"`
 }
 
+// ZoneSettings holds the changeable settings of a zone. Empty fields are not changed.
+type ZoneSettings struct {
+	Email       string `json:"email,omitempty"`
+	TTL         uint32 `json:"ttl,omitempty"`
+	Nameserver  string `json:"nameserver,omitempty"`
+	DNSSECEmail string `json:"dnssec_email,omitempty"`
+}
+
+// dnssecSettings switches the DNSSEC signing of a zone. Unlike ZoneSettings, false has to be sent as well.
+type dnssecSettings struct {
+	DNSSEC      bool   `json:"dnssec"`
+	DNSSECEmail string `json:"dnssec_email,omitempty"`
+}
+
 func (z HosttechZone) toLibdnsZone() libdns.Zone {
 	return libdns.Zone{
 		Name: z.Name,
 	}
 }
 
-func (t TLSARecord) toLibdnsRecord(zone string) libdns.Record {
-	return libdns.Record{
-		ID:    strconv.Itoa(t.Id),
-		Type:  t.Type,
-		Name:  libdns.RelativeName(t.Name, zone),
-		Value: t.Text,
-		TTL:   time.Duration(t.TTL * 1000000000),
+func (t TLSARecord) ToLibdnsRecord(zone string) libdns.Record {
+	return libdns.RR{
+		Type: "TLSA",
+		Name: libdnsOwnerName(t.Name, zone),
+		Data: t.TLSA.String(),
+		TTL:  intSecondsToDuration(t.TTL),
 	}
 }
 
-func (t TLSARecord) fromLibdnsRecord(record libdns.Record) HosttechRecord {
-	t.Name = record.Name
-	t.Type = record.Type
-	t.Text = record.Value
-	t.TTL = durationToIntSeconds(record.TTL)
+func (t TLSARecord) FromLibdnsRecord(record libdns.Record) (HosttechRecord, error) {
+	rr := record.RR()
+	tlsa, err := ParseTLSA(rr.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Name = hosttechOwnerName(rr.Name)
+	t.Type = "TLSA"
+	t.TLSA = tlsa
+	t.TTL = durationToIntSeconds(rr.TTL)
 	t.Comment = generateComment()
 
-	return t
+	return t, nil
 }
 
 func durationToIntSeconds(duration time.Duration) int {
@@ -239,15 +578,10 @@ func durationToIntSeconds(duration time.Duration) int {
 	return int(durationInSeconds)
 }
 
-func generateComment() string {
-	return fmt.Sprintf("This record was created or updated with libdns at %s UTC", time.Now().UTC().Format(time.Stamp))
+func intSecondsToDuration(seconds int) time.Duration {
+	return time.Duration(seconds) * time.Second
 }
 
-type ApiError struct {
-	s         string
-	ErrorCode int
-}
-
-func (a ApiError) Error() string {
-	return a.s
+func generateComment() string {
+	return fmt.Sprintf("This record was created or updated with libdns at %s UTC", time.Now().UTC().Format(time.Stamp))
 }
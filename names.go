@@ -0,0 +1,168 @@
+package hosttech
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// Owner names are relative to the zone in both libdns and Hosttech records. libdns names the zone apex "@", while
+// Hosttech uses an empty name. Wildcard names like "*" or "*.sub" are passed on as they are.
+// Owner names keep the case they were given in, but like all DNS names they are compared case-insensitively, so "www"
+// and "WWW" name the same RRset.
+// Targets of CNAME, MX, NS, SRV and PTR records are fully-qualified host names, which are always stored and returned
+// without a trailing dot.
+
+// relativeToZone returns the name relative to the zone, with "@" for the zone apex. The labels are compared
+// case-insensitively and only whole labels are removed. If the name is not inside the zone, it is returned without
+// its trailing dot and false.
+func relativeToZone(name string, zone string) (string, bool) {
+	name = RemoveTrailingDot(name)
+	zone = RemoveTrailingDot(zone)
+
+	if strings.EqualFold(name, zone) {
+		return "@", true
+	}
+	suffix := len(name) - len(zone) - 1
+	if zone != "" && suffix > 0 && name[suffix] == '.' && strings.EqualFold(name[suffix+1:], zone) {
+		return name[:suffix], true
+	}
+	return name, false
+}
+
+// libdnsOwnerName returns the owner name relative to the zone, with "@" for the zone apex. Names that are already
+// relative are returned as they are.
+func libdnsOwnerName(name string, zone string) string {
+	relativeName, _ := relativeToZone(name, zone)
+	if relativeName == "" {
+		return "@"
+	}
+	return relativeName
+}
+
+// inputOwnerName works like libdnsOwnerName, but rejects fully-qualified names with a trailing dot that are not
+// inside the zone with an error matching ErrValidation, instead of creating a record with that name in the zone.
+func inputOwnerName(name string, zone string) (string, error) {
+	if strings.HasSuffix(name, ".") && RemoveTrailingDot(zone) != "" {
+		if _, inside := relativeToZone(name, zone); !inside {
+			return "", fmt.Errorf(`%w: "%s" is not inside the zone "%s"`, ErrValidation, name, RemoveTrailingDot(zone))
+		}
+	}
+	return libdnsOwnerName(name, zone), nil
+}
+
+// hosttechOwnerName returns the relative owner name as stored by Hosttech, with an empty name for the zone apex
+func hosttechOwnerName(name string) string {
+	name = RemoveTrailingDot(name)
+	if name == "@" {
+		return ""
+	}
+	return name
+}
+
+// normalizeTarget returns the target host name without a trailing dot
+func normalizeTarget(target string) string {
+	return RemoveTrailingDot(target)
+}
+
+// normalizeRecord makes the owner name of an input record relative to the zone and removes the trailing dot of its
+// target, so that "sub", "sub.example.com." and "@", "", "example.com." are treated the same.
+func normalizeRecord(zone string, record libdns.Record) (libdns.Record, error) {
+	var err error
+	record = withName(record, func(name string) string {
+		ownerName, nameErr := inputOwnerName(name, zone)
+		if nameErr != nil {
+			err = nameErr
+			return name
+		}
+		return ownerName
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return withTarget(record, normalizeTarget), nil
+}
+
+// hosttechRecordOf converts an input record into its Hosttech representation, with A-labels and normalised names
+func hosttechRecordOf(zone string, record libdns.Record) (HosttechRecord, error) {
+	record, err := toASCIIRecord(record)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err = normalizeRecord(zone, record)
+	if err != nil {
+		return nil, err
+	}
+
+	return LibdnsRecordToHosttechRecordWrapper(record)
+}
+
+// withName returns a copy of the record with its name changed by rename. For SRV and SRVB records, only the name
+// after the service labels is changed.
+func withName(record libdns.Record, rename func(string) string) libdns.Record {
+	switch typed := record.(type) {
+	case libdns.Address:
+		typed.Name = rename(typed.Name)
+		return typed
+	case libdns.CAA:
+		typed.Name = rename(typed.Name)
+		return typed
+	case libdns.CNAME:
+		typed.Name = rename(typed.Name)
+		return typed
+	case libdns.MX:
+		typed.Name = rename(typed.Name)
+		return typed
+	case libdns.NS:
+		typed.Name = rename(typed.Name)
+		return typed
+	case libdns.SRV:
+		typed.Name = rename(typed.Name)
+		return typed
+	case libdns.ServiceBinding:
+		typed.Name = rename(typed.Name)
+		return typed
+	case libdns.TXT:
+		typed.Name = rename(typed.Name)
+		return typed
+	default:
+		rr := record.RR()
+		rr.Name = rename(rr.Name)
+		return rr
+	}
+}
+
+// withTarget returns a copy of the record with its target host name changed by rename. Records without a target are
+// returned as they are.
+func withTarget(record libdns.Record, rename func(string) string) libdns.Record {
+	switch typed := record.(type) {
+	case libdns.CNAME:
+		typed.Target = rename(typed.Target)
+		return typed
+	case libdns.MX:
+		typed.Target = rename(typed.Target)
+		return typed
+	case libdns.NS:
+		typed.Target = rename(typed.Target)
+		return typed
+	case libdns.SRV:
+		typed.Target = rename(typed.Target)
+		return typed
+	case libdns.RR:
+		// The target is the last field of the data of all these types
+		switch strings.ToUpper(typed.Type) {
+		case "CNAME", "MX", "NS", "SRV", "PTR":
+			fields := strings.Fields(typed.Data)
+			if len(fields) > 0 {
+				fields[len(fields)-1] = rename(fields[len(fields)-1])
+				typed.Data = strings.Join(fields, " ")
+			}
+		}
+		return typed
+	default:
+		return record
+	}
+}
@@ -0,0 +1,220 @@
+package hosttech
+
+import (
+	"encoding/json"
+	"github.com/libdns/libdns"
+	"github.com/stretchr/testify/assert"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestNormalizeRecord(t *testing.T) {
+	zone := "example.com"
+	ip := netip.MustParseAddr("192.0.2.1")
+
+	input := map[string]struct {
+		expectedResult libdns.Record
+		expectedOwner  string
+		expectedError  bool
+		ownerField     string
+		data           libdns.Record
+	}{
+		"A relative": {
+			expectedResult: libdns.Address{Name: "sub", IP: ip, TTL: time.Hour, ProviderData: 0},
+			expectedOwner:  "sub",
+			ownerField:     "name",
+			data:           libdns.Address{Name: "sub", IP: ip, TTL: time.Hour},
+		},
+		"A fully-qualified": {
+			expectedResult: libdns.Address{Name: "sub", IP: ip, TTL: time.Hour, ProviderData: 0},
+			expectedOwner:  "sub",
+			ownerField:     "name",
+			data:           libdns.Address{Name: "sub.example.com.", IP: ip, TTL: time.Hour},
+		},
+		"A apex with @": {
+			expectedResult: libdns.Address{Name: "@", IP: ip, TTL: time.Hour, ProviderData: 0},
+			expectedOwner:  "",
+			ownerField:     "name",
+			data:           libdns.Address{Name: "@", IP: ip, TTL: time.Hour},
+		},
+		"A apex empty": {
+			expectedResult: libdns.Address{Name: "@", IP: ip, TTL: time.Hour, ProviderData: 0},
+			expectedOwner:  "",
+			ownerField:     "name",
+			data:           libdns.Address{Name: "", IP: ip, TTL: time.Hour},
+		},
+		"A apex fully-qualified": {
+			expectedResult: libdns.Address{Name: "@", IP: ip, TTL: time.Hour, ProviderData: 0},
+			expectedOwner:  "",
+			ownerField:     "name",
+			data:           libdns.Address{Name: "example.com.", IP: ip, TTL: time.Hour},
+		},
+		"A different case": {
+			expectedResult: libdns.Address{Name: "Www", IP: ip, TTL: time.Hour, ProviderData: 0},
+			expectedOwner:  "Www",
+			ownerField:     "name",
+			data:           libdns.Address{Name: "Www.Example.COM.", IP: ip, TTL: time.Hour},
+		},
+		"A outside the zone": {
+			expectedError: true,
+			data:          libdns.Address{Name: "www.notexample.com.", IP: ip, TTL: time.Hour},
+		},
+		"TXT outside the zone": {
+			expectedError: true,
+			data:          libdns.TXT{Name: "x.otherexample.com.", Text: "token", TTL: time.Hour},
+		},
+		"A wildcard": {
+			expectedResult: libdns.Address{Name: "*", IP: ip, TTL: time.Hour, ProviderData: 0},
+			expectedOwner:  "*",
+			ownerField:     "name",
+			data:           libdns.Address{Name: "*.example.com.", IP: ip, TTL: time.Hour},
+		},
+		"AAAA wildcard below a name": {
+			expectedResult: libdns.Address{Name: "*.sub", IP: netip.MustParseAddr("2001:db8::1"), TTL: time.Hour, ProviderData: 0},
+			expectedOwner:  "*.sub",
+			ownerField:     "name",
+			data:           libdns.Address{Name: "*.sub", IP: netip.MustParseAddr("2001:db8::1"), TTL: time.Hour},
+		},
+		"CNAME target with trailing dot": {
+			expectedResult: libdns.CNAME{Name: "www", Target: "site.example.net", TTL: time.Hour, ProviderData: 0},
+			expectedOwner:  "www",
+			ownerField:     "name",
+			data:           libdns.CNAME{Name: "www.example.com.", Target: "site.example.net.", TTL: time.Hour},
+		},
+		"MX at apex": {
+			expectedResult: libdns.MX{Name: "@", Preference: 10, Target: "mail.example.com", TTL: time.Hour, ProviderData: 0},
+			expectedOwner:  "",
+			ownerField:     "ownername",
+			data:           libdns.MX{Name: "@", Preference: 10, Target: "mail.example.com.", TTL: time.Hour},
+		},
+		"NS delegation": {
+			expectedResult: libdns.NS{Name: "sub", Target: "ns1.example.net", TTL: time.Hour, ProviderData: 0},
+			expectedOwner:  "sub",
+			ownerField:     "ownername",
+			data:           libdns.NS{Name: "sub.example.com.", Target: "ns1.example.net.", TTL: time.Hour},
+		},
+		"TXT at apex": {
+			expectedResult: libdns.TXT{Name: "@", Text: "v=spf1 -all", TTL: time.Hour, ProviderData: 0},
+			expectedOwner:  "",
+			ownerField:     "name",
+			data:           libdns.TXT{Name: "", Text: "v=spf1 -all", TTL: time.Hour},
+		},
+		"SRV fully-qualified": {
+			expectedResult: libdns.SRV{Service: "sip", Transport: "tcp", Name: "sub", Priority: 10, Weight: 20, Port: 5060, Target: "sip.example.com", TTL: time.Hour, ProviderData: 0},
+			expectedOwner:  "_sip._tcp.sub",
+			ownerField:     "service",
+			data:           libdns.SRV{Service: "sip", Transport: "tcp", Name: "sub.example.com.", Priority: 10, Weight: 20, Port: 5060, Target: "sip.example.com.", TTL: time.Hour},
+		},
+		"SRV at apex": {
+			expectedResult: libdns.SRV{Service: "sip", Transport: "tcp", Name: "@", Priority: 10, Weight: 20, Port: 5060, Target: "sip.example.com", TTL: time.Hour, ProviderData: 0},
+			expectedOwner:  "_sip._tcp",
+			ownerField:     "service",
+			data:           libdns.SRV{Service: "sip", Transport: "tcp", Name: "", Priority: 10, Weight: 20, Port: 5060, Target: "sip.example.com", TTL: time.Hour},
+		},
+		"CAA at apex": {
+			expectedResult: libdns.CAA{Name: "@", Tag: "issue", Value: "letsencrypt.org", TTL: time.Hour, ProviderData: 0},
+			expectedOwner:  "",
+			ownerField:     "name",
+			data:           libdns.CAA{Name: "example.com.", Tag: "issue", Value: "letsencrypt.org", TTL: time.Hour},
+		},
+		"PTR target with trailing dot": {
+			expectedResult: libdns.RR{Type: "PTR", Name: "10", Data: "host.example.com", TTL: time.Hour},
+			expectedOwner:  "10",
+			ownerField:     "origin",
+			data:           libdns.RR{Type: "PTR", Name: "10", Data: "host.example.com.", TTL: time.Hour},
+		},
+		"TLSA fully-qualified": {
+			expectedResult: libdns.RR{Type: "TLSA", Name: "_443._tcp.www", Data: "3 1 1 d2abde240d7cd3ee6b4b28c54df034b97983a1d16e8a410e4561cb106618e971", TTL: time.Hour},
+			expectedOwner:  "_443._tcp.www",
+			ownerField:     "name",
+			data:           libdns.RR{Type: "TLSA", Name: "_443._tcp.www.example.com.", Data: "3 1 1 d2abde240d7cd3ee6b4b28c54df034b97983a1d16e8a410e4561cb106618e971", TTL: time.Hour},
+		},
+	}
+
+	for name, testStruct := range input {
+		t.Run(name, func(t *testing.T) {
+			normalized, err := normalizeRecord(zone, testStruct.data)
+			if testStruct.expectedError {
+				assert.ErrorIs(t, err, ErrValidation)
+				return
+			}
+			assert.NoError(t, err)
+			hosttechRecord, err := LibdnsRecordToHosttechRecordWrapper(normalized)
+			assert.NoError(t, err)
+
+			var fields map[string]any
+			bodyBytes, _ := json.Marshal(hosttechRecord)
+			_ = json.Unmarshal(bodyBytes, &fields)
+			owner, _ := fields[testStruct.ownerField].(string)
+			assert.Equal(t, testStruct.expectedOwner, owner)
+
+			output := hosttechRecord.ToLibdnsRecord(zone)
+			assert.Equal(t, testStruct.expectedResult, output)
+
+			// The normalised record is stable on a further round trip
+			normalized, err = normalizeRecord(zone, output)
+			assert.NoError(t, err)
+			roundTrip, err := LibdnsRecordToHosttechRecordWrapper(normalized)
+			assert.NoError(t, err)
+			assert.Equal(t, output, roundTrip.ToLibdnsRecord(zone))
+		})
+	}
+}
+
+func TestLibdnsOwnerName(t *testing.T) {
+	input := map[string]struct {
+		expectedResult string
+		expectedError  bool
+		name           string
+	}{
+		"Relative":                    {expectedResult: "sub", name: "sub"},
+		"Fully-qualified":             {expectedResult: "sub", name: "sub.example.com."},
+		"Apex empty":                  {expectedResult: "@", name: ""},
+		"Apex @":                      {expectedResult: "@", name: "@"},
+		"Apex fully-qualified":        {expectedResult: "@", name: "example.com."},
+		"Wildcard":                    {expectedResult: "*.sub", name: "*.sub.example.com."},
+		"Different case":              {expectedResult: "Www", name: "Www.Example.COM."},
+		"Apex in different case":      {expectedResult: "@", name: "EXAMPLE.com."},
+		"Relative ending like a zone": {expectedResult: "www.notexample.com", name: "www.notexample.com"},
+		"Suffix without a label":      {expectedError: true, name: "www.notexample.com."},
+		"Outside the zone":            {expectedError: true, name: "x.example.org."},
+	}
+
+	for name, testStruct := range input {
+		t.Run(name, func(t *testing.T) {
+			output, err := inputOwnerName(testStruct.name, "example.com.")
+
+			if testStruct.expectedError {
+				assert.ErrorIs(t, err, ErrValidation)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testStruct.expectedResult, output)
+			assert.Equal(t, testStruct.expectedResult, libdnsOwnerName(testStruct.name, "example.com."))
+		})
+	}
+}
+
+func TestRelativeToZone(t *testing.T) {
+	input := map[string]struct {
+		expectedResult string
+		expectedInside bool
+		name           string
+	}{
+		"Inside the zone":        {expectedResult: "www", expectedInside: true, name: "www.example.com."},
+		"Different case":         {expectedResult: "_acme-challenge.WWW", expectedInside: true, name: "_acme-challenge.WWW.Example.COM."},
+		"Apex":                   {expectedResult: "@", expectedInside: true, name: "EXAMPLE.com"},
+		"Suffix without a label": {expectedResult: "www.notexample.com", expectedInside: false, name: "www.notexample.com."},
+		"Other zone":             {expectedResult: "www.example.org", expectedInside: false, name: "www.example.org."},
+	}
+
+	for name, testStruct := range input {
+		t.Run(name, func(t *testing.T) {
+			output, inside := relativeToZone(testStruct.name, "example.com.")
+
+			assert.Equal(t, testStruct.expectedInside, inside)
+			assert.Equal(t, testStruct.expectedResult, output)
+		})
+	}
+}
@@ -1,14 +1,20 @@
 // Package hosttech implements methods for manipulating Hosttech.ch DNS records with the libdns interfaces.
 // Manipulation is achieved with the Hosttech API at https://api.ns1.hosttech.eu/api/documentation/#/.
+// Records are returned as the typed structs of libdns (libdns.Address, libdns.TXT, ...), with the id of the Hosttech
+// record stored in their ProviderData as an int.
 package hosttech
 
 import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/libdns/libdns"
 )
@@ -16,148 +22,385 @@ import (
 // Provider facilitates DNS record manipulation with Hosttech.ch.
 type Provider struct {
 	APIToken string `json:"api_token,omitempty"`
+	// BaseURL overrides the URL of the Hosttech API, e.g. to use a staging endpoint or a local stub.
+	// If it is empty, the public Hosttech API is used.
+	BaseURL string `json:"base_url,omitempty"`
+	// HTTPClient is used for all calls to the Hosttech API, which allows setting timeouts, proxies or TLS settings.
+	// If it is nil, http.DefaultClient is used.
+	HTTPClient *http.Client `json:"-"`
+	// Retry configures how failed API calls are retried. If it is nil, the default policy is used.
+	Retry *RetryPolicy `json:"retry,omitempty"`
+	// ContinueOnError makes AppendRecords, SetRecords and DeleteRecords attempt all records, instead of stopping at the
+	// first failing record. All failures are reported at the end in a BatchError.
+	ContinueOnError bool `json:"continue_on_error,omitempty"`
+	// MaxConcurrency is the maximal number of API calls AppendRecords, SetRecords and DeleteRecords send at once.
+	// The returned records keep the order of the input. Defaults to 1, which processes the records sequentially.
+	MaxConcurrency int `json:"max_concurrency,omitempty"`
+	// RateLimit limits the calls to the Hosttech API on the client side. All providers of the process using the same
+	// API token share one limit. If it is nil, calls are not limited.
+	RateLimit *RateLimit `json:"rate_limit,omitempty"`
+	// RateLimiter limits the calls to the Hosttech API with the given limiter, which may be shared with other
+	// providers. It takes precedence over RateLimit.
+	RateLimiter *RateLimiter `json:"-"`
+	// ZoneCacheTTL is how long FindZone and the FQDN methods reuse the zones of the account. Defaults to 5 minutes.
+	ZoneCacheTTL time.Duration `json:"zone_cache_ttl,omitempty"`
+	// UnicodeNames makes the provider return internationalised domain names with U-labels, e.g. "bücher.ch" instead of
+	// "xn--bcher-kva.ch". Input names are accepted in both forms regardless of this setting.
+	UnicodeNames bool `json:"unicode_names,omitempty"`
+
+	zoneCache zoneCache
 }
 
 // The URL for the Hosttech API connection
 const apiHost = "https://api.ns1.hosttech.eu/api/user/v1"
 
-// GetRecords lists all the records in the zone.
-func (p *Provider) GetRecords(ctx context.Context, zone string) ([]libdns.Record, error) {
-	reqURL := fmt.Sprintf("%s/zones/%s/records", apiHost, RemoveTrailingDot(zone))
+func (p *Provider) apiURL() string {
+	if p.BaseURL == "" {
+		return apiHost
+	}
+	return strings.TrimRight(p.BaseURL, "/")
+}
 
-	responseBody, err := p.makeApiCall(ctx, http.MethodGet, reqURL, nil)
+// zoneURL returns the URL of the zone, with the name converted to A-labels
+func (p *Provider) zoneURL(name string) (string, error) {
+	name, err := toASCIIName(RemoveTrailingDot(name))
+	if err != nil {
+		return "", err
+	}
 
-	//If there's an error return an empty slice
+	return fmt.Sprintf("%s/zones/%s", p.apiURL(), name), nil
+}
+
+func (p *Provider) retryPolicy() RetryPolicy {
+	if p.Retry == nil {
+		return defaultRetryPolicy
+	}
+	return p.Retry.withDefaults()
+}
+
+func (p *Provider) rateLimiter() *RateLimiter {
+	if p.RateLimiter != nil {
+		return p.RateLimiter
+	}
+	if p.RateLimit != nil {
+		return SharedRateLimiter(rateLimiterKey(p.APIToken), *p.RateLimit)
+	}
+	return nil
+}
+
+func (p *Provider) httpClient() *http.Client {
+	if p.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return p.HTTPClient
+}
+
+// GetRecords lists all the records in the zone.
+func (p *Provider) GetRecords(ctx context.Context, zone string) ([]libdns.Record, error) {
+	zone, err := toASCIIName(zone)
 	if err != nil {
 		return []libdns.Record{}, err
 	}
 
-	var parsedResponse = HosttechListResponseWrapper{}
-	err = json.Unmarshal(responseBody, &parsedResponse)
+	hosttechRecords, err := p.listRecords(ctx, zone)
 
+	//If there's an error return an empty slice
 	if err != nil {
 		return []libdns.Record{}, err
 	}
 
 	var libdnsRecords []libdns.Record
-	for _, record := range parsedResponse.Data {
-		libdnsRecords = append(libdnsRecords, record.toLibdnsRecord(zone))
+	for _, record := range hosttechRecords {
+		libdnsRecords = append(libdnsRecords, record.ToLibdnsRecord(zone))
 	}
 
-	return libdnsRecords, nil
+	return p.presentRecords(libdnsRecords), nil
 }
 
-// AppendRecords adds records to the zone. It returns all records that were added.
-// If an error occurs while records are being added, the already successfully added records will be returned along with an error.
-func (p *Provider) AppendRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
-	reqURL := fmt.Sprintf("%s/zones/%s/records", apiHost, RemoveTrailingDot(zone))
-
-	successfullyAppendedRecords := []libdns.Record{}
-	for _, record := range records {
+// RecordFilter selects records in GetRecordsFiltered. Empty fields match any record.
+type RecordFilter struct {
+	// Type is the type of the records, e.g. "TXT". It is filtered by Hosttech.
+	Type string
+	// Name is the exact name of the records. It may be relative to the zone, "@" for the apex, or fully-qualified.
+	Name string
+	// NameSuffix matches records whose name ends with the given labels, e.g. "api" matches "api" and
+	// "_acme-challenge.api", but not "myapi". Like Name, it may be relative to the zone or fully-qualified.
+	NameSuffix string
+}
 
-		hosttechRecord, err := LibdnsRecordToHosttechRecordWrapper(record)
-		if err != nil {
-			return nil, err
+// normalize converts the names of the filter to A-labels relative to the zone, the way record names are returned
+func (f RecordFilter) normalize(zone string) (RecordFilter, error) {
+	for _, name := range []*string{&f.Name, &f.NameSuffix} {
+		if *name == "" {
+			continue
 		}
-
-		bodyBytes, err := json.Marshal(hosttechRecord)
+		asciiName, err := toASCIIName(*name)
 		if err != nil {
-			return nil, err
+			return f, err
 		}
-
-		responseBody, err := p.makeApiCall(ctx, http.MethodPost, reqURL, bytes.NewReader(bodyBytes))
-		if err != nil {
-			return successfullyAppendedRecords, err
+		if *name, err = inputOwnerName(asciiName, zone); err != nil {
+			return f, err
 		}
+	}
+	return f, nil
+}
 
-		var parsedResponse = HosttechSingleResponseWrapper{}
-		err = json.Unmarshal(responseBody, &parsedResponse)
+// matches reports whether the record is selected by the normalized filter. Names are compared case-insensitively.
+func (f RecordFilter) matches(rr libdns.RR) bool {
+	if f.Type != "" && !strings.EqualFold(rr.Type, f.Type) {
+		return false
+	}
+	name := strings.ToLower(rr.Name)
+	if f.Name != "" && name != strings.ToLower(f.Name) {
+		return false
+	}
+	// Every name of the zone ends with the labels of the zone apex
+	suffix := strings.ToLower(f.NameSuffix)
+	if suffix != "" && suffix != "@" && name != suffix && !strings.HasSuffix(name, "."+suffix) {
+		return false
+	}
+	return true
+}
 
-		if err != nil {
-			return []libdns.Record{}, err
+// GetRecordsFiltered lists the records in the zone that match the filter. The type is filtered by Hosttech, which
+// avoids downloading the whole zone. If Hosttech rejects the filter, the whole zone is fetched and filtered locally.
+func (p *Provider) GetRecordsFiltered(ctx context.Context, zone string, filter RecordFilter) ([]libdns.Record, error) {
+	zone, err := toASCIIName(zone)
+	if err != nil {
+		return []libdns.Record{}, err
+	}
+	filter, err = filter.normalize(zone)
+	if err != nil {
+		return []libdns.Record{}, err
+	}
+
+	hosttechRecords, err := p.listRecordsOfType(ctx, zone, strings.ToUpper(filter.Type))
+	if errors.Is(err, ErrValidation) {
+		hosttechRecords, err = p.listRecords(ctx, zone)
+	}
+	if err != nil {
+		return []libdns.Record{}, err
+	}
+
+	libdnsRecords := []libdns.Record{}
+	for _, record := range hosttechRecords {
+		libdnsRecord := record.ToLibdnsRecord(zone)
+		if filter.matches(libdnsRecord.RR()) {
+			libdnsRecords = append(libdnsRecords, libdnsRecord)
 		}
+	}
+
+	return p.presentRecords(libdnsRecords), nil
+}
+
+// GetRecord fetches the record with the given Hosttech id. It returns the record both converted to libdns and in its
+// Hosttech representation. If the record does not exist, an error matching ErrNotFound is returned.
+func (p *Provider) GetRecord(ctx context.Context, zone string, id int) (libdns.Record, HosttechRecord, error) {
+	zone, err := toASCIIName(zone)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	zoneURL, err := p.zoneURL(zone)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		successfullyAppendedRecords = append(successfullyAppendedRecords, parsedResponse.Data.toLibdnsRecord(zone))
+	responseBody, err := p.makeApiCall(ctx, http.MethodGet, fmt.Sprintf("%s/records/%d", zoneURL, id), nil)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return successfullyAppendedRecords, nil
+	var parsedResponse = HosttechSingleResponseWrapper{}
+	err = json.Unmarshal(responseBody, &parsedResponse)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return p.presentRecords([]libdns.Record{parsedResponse.Data.ToLibdnsRecord(zone)})[0], parsedResponse.Data.value, nil
 }
 
-// SetRecords sets the records in the zone, either by updating existing records or creating new ones.
-// It returns the updated records.
-func (p *Provider) SetRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
-	successfullyUpdatedRecords := []libdns.Record{}
-	for _, record := range records {
+// AppendRecords adds records to the zone. It returns all records that were added.
+// If an error occurs while records are being added, the already successfully added records will be returned along with
+// a BatchError. Unless ContinueOnError is set, no further records are added after the first failure.
+func (p *Provider) AppendRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	zone, err := toASCIIName(zone)
+	if err != nil {
+		return []libdns.Record{}, err
+	}
 
-		hosttechRecord, err := LibdnsRecordToHosttechRecordWrapper(record)
+	appendedRecords := make([]libdns.Record, len(records))
+	failures := p.runBatch(ctx, len(records), func(i int) *RecordError {
+		hosttechRecord, err := hosttechRecordOf(zone, records[i])
 		if err != nil {
-			return nil, err
+			failure := recordError("convert", records[i], err)
+			return &failure
 		}
-		bodyBytes, err := json.Marshal(hosttechRecord)
 
+		appendedRecords[i], err = p.createRecord(ctx, zone, hosttechRecord)
 		if err != nil {
-			return nil, err
+			failure := recordError("create", records[i], err)
+			return &failure
 		}
 
-		reqURL := fmt.Sprintf("%s/zones/%s/records/%s", apiHost, RemoveTrailingDot(zone), record.ID)
+		return nil
+	})
 
-		responseBody, err := p.makeApiCall(ctx, http.MethodPut, reqURL, bytes.NewReader(bodyBytes))
+	return p.presentRecords(compactRecords(appendedRecords)), newBatchError(failures)
+}
+
+// SetRecords sets the records in the zone, so that for each (name, type) pair in the input, the records of the input
+// are the only records in the zone with that pair. Existing records are updated in place where possible, missing
+// records are created and all other records with the same name and type are deleted. It returns the records which were set.
+// If any input record can not be converted, nothing is changed. The Hosttech API has no batch operations, so if an
+// error occurs later on, the zone may be left partially updated: the records which were set are returned along with a
+// BatchError. Unless ContinueOnError is set, no further changes are made after the first failure.
+func (p *Provider) SetRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	zone, err := toASCIIName(zone)
+	if err != nil {
+		return []libdns.Record{}, err
+	}
 
+	desiredRecords := make([]HosttechRecord, 0, len(records))
+	var failures []RecordError
+	for _, record := range records {
+		hosttechRecord, err := hosttechRecordOf(zone, record)
 		if err != nil {
-			//If the error doesn't have anything to do with the api, return
-			apiError, ok := err.(ApiError)
-			if !ok {
-				return successfullyUpdatedRecords, err
-			}
+			failures = append(failures, recordError("convert", record, err))
+			continue
+		}
+		desiredRecords = append(desiredRecords, hosttechRecord)
+	}
+	if len(failures) > 0 {
+		return []libdns.Record{}, newBatchError(failures)
+	}
 
-			//If the error isn't a 404, return
-			if apiError.ErrorCode != 404 {
-				return successfullyUpdatedRecords, err
-			}
+	existingRecords, err := p.listRecords(ctx, zone)
+	if err != nil {
+		return []libdns.Record{}, err
+	}
+
+	plan := planRecordSets(zone, existingRecords, desiredRecords)
 
-			//If the error was a 404, the record could not be updated because it didn't exist. So we create a new one
-			appendedRecords, err := p.AppendRecords(ctx, zone, []libdns.Record{record})
+	updatedRecords := make([]libdns.Record, len(plan.changes))
+	failures = p.runBatch(ctx, len(plan.changes), func(i int) *RecordError {
+		change := plan.changes[i]
+		var err error
+		switch {
+		case change.existing == nil:
+			updatedRecords[i], err = p.createRecord(ctx, zone, change.desired)
 			if err != nil {
-				return successfullyUpdatedRecords, err
+				failure := recordError("create", records[i], err)
+				return &failure
+			}
+		case change.unchanged:
+			updatedRecords[i] = change.existing.ToLibdnsRecord(zone)
+		default:
+			updatedRecords[i], err = p.updateRecord(ctx, zone, change.existing.base().Id, change.desired)
+			if err != nil {
+				failure := recordError("update", records[i], err)
+				return &failure
 			}
-
-			successfullyUpdatedRecords = append(successfullyUpdatedRecords, appendedRecords...)
-			continue
 		}
+		return nil
+	})
+	if len(failures) > 0 && !p.continueOnError(ctx) {
+		return p.presentRecords(compactRecords(updatedRecords)), newBatchError(failures)
+	}
 
-		var parsedResponse = HosttechSingleResponseWrapper{}
-		err = json.Unmarshal(responseBody, &parsedResponse)
+	failures = append(failures, p.runBatch(ctx, len(plan.deletions), func(i int) *RecordError {
+		err := p.deleteRecord(ctx, zone, plan.deletions[i].base().Id)
 		if err != nil {
-			return []libdns.Record{}, err
+			failure := recordError("delete", plan.deletions[i].ToLibdnsRecord(zone), err)
+			return &failure
 		}
+		return nil
+	})...)
 
-		successfullyUpdatedRecords = append(successfullyUpdatedRecords, parsedResponse.Data.toLibdnsRecord(zone))
-	}
-
-	return successfullyUpdatedRecords, nil
+	return p.presentRecords(compactRecords(updatedRecords)), newBatchError(failures)
 }
 
 // DeleteRecords deletes the records from the zone. It returns the records that were deleted.
-// If an error occurs while records are being deleted, the already successfully deleted records will be returned along with an error.
+// Records carrying a Hosttech id in their ProviderData are deleted by that id. All other records are matched by name and
+// optionally type, TTL and value, where empty fields match any record. Every matching record is deleted, input records
+// without a match are ignored.
+// If an input record can not be converted, nothing is deleted, unless ContinueOnError is set: then the other records
+// are still deleted and the conversion failure is reported in the BatchError.
+// If an error occurs while records are being deleted, the already successfully deleted records will be returned along
+// with a BatchError. Unless ContinueOnError is set, no further records are deleted after the first failure.
 func (p *Provider) DeleteRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
-	successfullyDeletedRecords := []libdns.Record{}
+	zone, err := toASCIIName(zone)
+	if err != nil {
+		return []libdns.Record{}, err
+	}
+
+	existingRecords, err := p.listRecords(ctx, zone)
+	if err != nil {
+		return []libdns.Record{}, err
+	}
+
+	// A record matched by several inputs is only deleted once
+	var matchingRecords []HosttechRecord
+	var failures []RecordError
+	matched := make([]bool, len(existingRecords))
 	for _, record := range records {
-		reqUrl := fmt.Sprintf("%s/zones/%s/records/%s", apiHost, RemoveTrailingDot(zone), record.ID)
-		_, err := p.makeApiCall(ctx, http.MethodDelete, reqUrl, nil)
+		asciiRecord, err := toASCIIRecord(record)
+		if err == nil {
+			asciiRecord, err = normalizeRecord(zone, asciiRecord)
+		}
+		if err != nil {
+			failures = append(failures, recordError("convert", record, err))
+			if !p.continueOnError(ctx) {
+				return []libdns.Record{}, newBatchError(failures)
+			}
+			continue
+		}
+		for i, existing := range existingRecords {
+			if !matched[i] && recordMatches(zone, existing, asciiRecord) {
+				matched[i] = true
+				matchingRecords = append(matchingRecords, existing)
+			}
+		}
+	}
 
+	deletedRecords := make([]libdns.Record, len(matchingRecords))
+	failures = append(failures, p.runBatch(ctx, len(matchingRecords), func(i int) *RecordError {
+		record := matchingRecords[i].ToLibdnsRecord(zone)
+		err := p.deleteRecord(ctx, zone, matchingRecords[i].base().Id)
 		if err != nil {
-			return successfullyDeletedRecords, err
+			failure := recordError("delete", record, err)
+			return &failure
 		}
 
-		successfullyDeletedRecords = append(successfullyDeletedRecords, record)
-	}
+		deletedRecords[i] = record
+		return nil
+	})...)
 
-	return successfullyDeletedRecords, nil
+	return p.presentRecords(compactRecords(deletedRecords)), newBatchError(failures)
+}
+
+// continueOnError reports whether a batch operation should go on after a record failed
+func (p *Provider) continueOnError(ctx context.Context) bool {
+	return p.ContinueOnError && ctx.Err() == nil
 }
 
 // List all available zones
 func (p *Provider) ListZones(ctx context.Context) ([]libdns.Zone, error) {
-	reqUrl := fmt.Sprintf("%s/zones", apiHost)
+	zones, err := p.listZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range zones {
+		zones[i].Name = p.presentName(zones[i].Name)
+	}
+
+	return zones, nil
+}
+
+// listZones fetches all zones with the names as stored by Hosttech
+func (p *Provider) listZones(ctx context.Context) ([]libdns.Zone, error) {
+	reqUrl := fmt.Sprintf("%s/zones", p.apiURL())
 	responseBody, err := p.makeApiCall(ctx, http.MethodGet, reqUrl, nil)
 
 	if err != nil {
@@ -179,31 +422,357 @@ func (p *Provider) ListZones(ctx context.Context) ([]libdns.Zone, error) {
 	return libdnsZones, nil
 }
 
-func (p *Provider) makeApiCall(ctx context.Context, httpMethod string, reqUrl string, body io.Reader) (response []byte, err error) {
-	req, err := http.NewRequestWithContext(ctx, httpMethod, reqUrl, body)
-	req.Header.Set("Authorization", "Bearer "+p.APIToken)
-	req.Header.Set("Content-Type", "application/json")
+// CreateZone creates a new zone with the name, email, TTL, nameserver and DNSSEC settings of the given zone.
+// It returns the zone as it was created by Hosttech.
+func (p *Provider) CreateZone(ctx context.Context, zone HosttechZone) (HosttechZone, error) {
+	reqURL := fmt.Sprintf("%s/zones", p.apiURL())
+	zone.Id = 0
+	name, err := toASCIIName(RemoveTrailingDot(zone.Name))
+	if err != nil {
+		return HosttechZone{}, err
+	}
+	zone.Name = name
+
+	bodyBytes, err := json.Marshal(zone)
+	if err != nil {
+		return HosttechZone{}, err
+	}
+
+	defer p.invalidateZoneCache()
+
+	return p.sendZone(ctx, http.MethodPost, reqURL, bodyBytes)
+}
+
+// GetZone fetches a single zone including its settings
+func (p *Provider) GetZone(ctx context.Context, name string) (HosttechZone, error) {
+	reqURL, err := p.zoneURL(name)
+	if err != nil {
+		return HosttechZone{}, err
+	}
+
+	return p.sendZone(ctx, http.MethodGet, reqURL, nil)
+}
+
+// UpdateZone changes the settings of the zone. Settings with a zero value are left untouched.
+// It returns the zone as it was stored by Hosttech.
+func (p *Provider) UpdateZone(ctx context.Context, name string, settings ZoneSettings) (HosttechZone, error) {
+	reqURL, err := p.zoneURL(name)
+	if err != nil {
+		return HosttechZone{}, err
+	}
+
+	bodyBytes, err := json.Marshal(settings)
+	if err != nil {
+		return HosttechZone{}, err
+	}
+
+	return p.sendZone(ctx, http.MethodPut, reqURL, bodyBytes)
+}
+
+// EnableDNSSEC turns on DNSSEC signing for the zone. Hosttech notifies the given email address about key rollovers.
+// The DS records for the registrar are available through GetDNSSEC once the zone is signed.
+func (p *Provider) EnableDNSSEC(ctx context.Context, zone string, email string) (HosttechZone, error) {
+	return p.setDNSSEC(ctx, zone, dnssecSettings{DNSSEC: true, DNSSECEmail: email})
+}
+
+// DisableDNSSEC turns off DNSSEC signing for the zone. The DS records should be removed at the registrar beforehand.
+func (p *Provider) DisableDNSSEC(ctx context.Context, zone string) (HosttechZone, error) {
+	return p.setDNSSEC(ctx, zone, dnssecSettings{DNSSEC: false})
+}
+
+func (p *Provider) setDNSSEC(ctx context.Context, zone string, settings dnssecSettings) (HosttechZone, error) {
+	reqURL, err := p.zoneURL(zone)
+	if err != nil {
+		return HosttechZone{}, err
+	}
+
+	bodyBytes, err := json.Marshal(settings)
+	if err != nil {
+		return HosttechZone{}, err
+	}
+
+	return p.sendZone(ctx, http.MethodPut, reqURL, bodyBytes)
+}
+
+// GetDNSSEC fetches the DNSKEY and DS data of a signed zone. If Hosttech only provides the keys, the SHA-256 DS records
+// are computed from the key signing keys.
+func (p *Provider) GetDNSSEC(ctx context.Context, zone string) (DNSSECInfo, error) {
+	zone, err := toASCIIName(zone)
+	if err != nil {
+		return DNSSECInfo{}, err
+	}
+
+	zoneURL, err := p.zoneURL(zone)
+	if err != nil {
+		return DNSSECInfo{}, err
+	}
+
+	responseBody, err := p.makeApiCall(ctx, http.MethodGet, zoneURL+"/dnssec", nil)
+	if err != nil {
+		return DNSSECInfo{}, zoneError(err)
+	}
+
+	var parsedResponse = DNSSECInfoResponseWrapper{}
+	err = json.Unmarshal(responseBody, &parsedResponse)
+	if err != nil {
+		return DNSSECInfo{}, err
+	}
+
+	return parsedResponse.Data.withDerivedDSRecords(zone)
+}
+
+// DeleteZone deletes the zone with all its records. As a safety measure, zones that contain any records besides the
+// NS records of the zone apex are only deleted if force is set; otherwise an error matching ErrZoneNotEmpty is returned.
+func (p *Provider) DeleteZone(ctx context.Context, name string, force bool) error {
+	name, err := toASCIIName(name)
+	if err != nil {
+		return err
+	}
+
+	if !force {
+		records, err := p.listRecords(ctx, name)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			rr := record.ToLibdnsRecord(name).RR()
+			if rr.Type != "NS" || rr.Name != "@" {
+				return fmt.Errorf(`%w: zone "%s" still contains records, e.g. "%s %s"`, ErrZoneNotEmpty, name, rr.Name, rr.Type)
+			}
+		}
+	}
+
+	reqURL, err := p.zoneURL(name)
+	if err != nil {
+		return err
+	}
+	_, err = p.makeApiCall(ctx, http.MethodDelete, reqURL, nil)
+	p.invalidateZoneCache()
+
+	return zoneError(err)
+}
+
+func (p *Provider) sendZone(ctx context.Context, httpMethod string, reqURL string, bodyBytes []byte) (HosttechZone, error) {
+	responseBody, err := p.makeApiCall(ctx, httpMethod, reqURL, bodyBytes)
+	if err != nil {
+		return HosttechZone{}, zoneError(err)
+	}
+
+	var parsedResponse = HosttechZoneSingleResponseWrapper{}
+	err = json.Unmarshal(responseBody, &parsedResponse)
+	if err != nil {
+		return HosttechZone{}, err
+	}
+
+	return parsedResponse.Data, nil
+}
+
+// listRecords fetches all records of the zone in their Hosttech representation.
+func (p *Provider) listRecords(ctx context.Context, zone string) ([]HosttechRecord, error) {
+	return p.listRecordsOfType(ctx, zone, "")
+}
+
+// listRecordsOfType fetches the records of the zone with the given type, or all records if the type is empty.
+// The type is filtered by Hosttech, so the result has to be checked, in case the filter is not applied.
+func (p *Provider) listRecordsOfType(ctx context.Context, zone string, recordType string) ([]HosttechRecord, error) {
+	zoneURL, err := p.zoneURL(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	reqURL := zoneURL + "/records"
+	if recordType != "" {
+		reqURL += "?" + url.Values{"type": {recordType}}.Encode()
+	}
+
+	responseBody, err := p.makeApiCall(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, zoneError(err)
+	}
+
+	var parsedResponse = HosttechListResponseWrapper{}
+	err = json.Unmarshal(responseBody, &parsedResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	hosttechRecords := make([]HosttechRecord, 0, len(parsedResponse.Data))
+	for _, record := range parsedResponse.Data {
+		hosttechRecords = append(hosttechRecords, record.value)
+	}
+
+	return hosttechRecords, nil
+}
+
+// createRecord creates a new record in the zone and returns it as it was stored by Hosttech.
+// The comment of the record is tagged with a marker unique to this call. If the creation fails in a way that leaves
+// it unclear whether Hosttech stored the record, the zone is listed again and an already created record carrying the
+// marker is returned, instead of creating a duplicate by sending the request again. This check is also done after the
+// last attempt. The request is sent at most RetryPolicy.MaxAttempts times in total, including retries on rate limiting.
+func (p *Provider) createRecord(ctx context.Context, zone string, record HosttechRecord) (libdns.Record, error) {
+	zoneURL, err := p.zoneURL(zone)
+	if err != nil {
+		return nil, err
+	}
+	reqURL := zoneURL + "/records"
+
+	marker, err := generateRequestMarker()
+	if err != nil {
+		return nil, err
+	}
+	bodyBytes, err := marshalWithComment(record, fmt.Sprintf("%s [%s]", record.base().Comment, marker))
+	if err != nil {
+		return nil, err
+	}
+
+	policy := p.retryPolicy()
+	for attempt := 1; ; attempt++ {
+		responseBody, retryAfter, err := p.doApiCall(ctx, http.MethodPost, reqURL, bodyBytes)
+		if err == nil {
+			return parseRecordResponse(zone, responseBody)
+		}
+
+		switch {
+		case ambiguous(ctx, err):
+			existingRecords, listErr := p.listRecords(ctx, zone)
+			if listErr != nil {
+				// Without a listing, sending the request again could create a duplicate
+				return nil, zoneError(err)
+			}
+			for _, existing := range existingRecords {
+				if createdBy(zone, existing, record, marker) {
+					return existing.ToLibdnsRecord(zone), nil
+				}
+			}
+		case !policy.retryable(ctx, http.MethodPost, err):
+			return nil, zoneError(err)
+		}
+
+		if attempt >= policy.MaxAttempts || !sleepContext(ctx, policy.delay(attempt, retryAfter)) {
+			return nil, zoneError(err)
+		}
+	}
+}
+
+// updateRecord overwrites the record with the given id and returns it as it was stored by Hosttech.
+func (p *Provider) updateRecord(ctx context.Context, zone string, id int, record HosttechRecord) (libdns.Record, error) {
+	zoneURL, err := p.zoneURL(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.sendRecord(ctx, zone, http.MethodPut, fmt.Sprintf("%s/records/%d", zoneURL, id), bodyBytes)
+}
+
+func (p *Provider) sendRecord(ctx context.Context, zone string, httpMethod string, reqURL string, bodyBytes []byte) (libdns.Record, error) {
+	responseBody, err := p.makeApiCall(ctx, httpMethod, reqURL, bodyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRecordResponse(zone, responseBody)
+}
+
+// parseRecordResponse parses the single record returned by the Hosttech API
+func parseRecordResponse(zone string, responseBody []byte) (libdns.Record, error) {
+	var parsedResponse = HosttechSingleResponseWrapper{}
+	err := json.Unmarshal(responseBody, &parsedResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsedResponse.Data.ToLibdnsRecord(zone), nil
+}
+
+// deleteRecord deletes the record with the given id from the zone.
+func (p *Provider) deleteRecord(ctx context.Context, zone string, id int) error {
+	zoneURL, err := p.zoneURL(zone)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.makeApiCall(ctx, http.MethodDelete, fmt.Sprintf("%s/records/%d", zoneURL, id), nil)
+
+	return err
+}
+
+// makeApiCall sends the request to the Hosttech API and returns the body of the response. Failed calls are retried
+// according to the retry policy of the provider.
+// If a DELETE is retried after an attempt which may have been processed, a 404 means that the earlier attempt
+// succeeded, so it is not reported as an error.
+func (p *Provider) makeApiCall(ctx context.Context, httpMethod string, reqUrl string, body []byte) (response []byte, err error) {
+	policy := p.retryPolicy()
+
+	var mayHaveSucceeded bool
+	for attempt := 1; ; attempt++ {
+		var retryAfter time.Duration
+		response, retryAfter, err = p.doApiCall(ctx, httpMethod, reqUrl, body)
+		if httpMethod == http.MethodDelete && mayHaveSucceeded && errors.Is(err, ErrNotFound) {
+			return nil, nil
+		}
+		if err == nil || attempt >= policy.MaxAttempts || !policy.retryable(ctx, httpMethod, err) {
+			return
+		}
+		mayHaveSucceeded = mayHaveSucceeded || ambiguous(ctx, err)
+
+		if !sleepContext(ctx, policy.delay(attempt, retryAfter)) {
+			return
+		}
+	}
+}
+
+// doApiCall sends a single request to the Hosttech API. Next to the body of the response, it returns the duration
+// Hosttech asked to wait before trying again, if any.
+func (p *Provider) doApiCall(ctx context.Context, httpMethod string, reqUrl string, body []byte) ([]byte, time.Duration, error) {
+	limiter := p.rateLimiter()
+	if limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, httpMethod, reqUrl, bodyReader)
 
 	//Return nil if there's an error
 	if err != nil {
-		return
+		return nil, 0, err
 	}
+	req.Header.Set("Authorization", "Bearer "+p.APIToken)
+	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := p.httpClient().Do(req)
 
 	//Return an empty slice if there's an error
 	if err != nil {
-		return
+		return nil, 0, err
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, ApiError{
-			s:         fmt.Sprintf("call to API was not successful, returned the status code '%s'", resp.Status),
-			ErrorCode: resp.StatusCode,
+		// The body is only used to describe the error, so a failure to read it is ignored
+		errorBody, _ := io.ReadAll(resp.Body)
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+		if limiter != nil && resp.StatusCode == http.StatusTooManyRequests {
+			limiter.throttle(retryAfter)
 		}
+		return nil, retryAfter, newApiError(resp.Status, resp.StatusCode, errorBody)
+	}
+	if limiter != nil {
+		limiter.recover()
 	}
 
-	return io.ReadAll(resp.Body)
+	response, err := io.ReadAll(resp.Body)
+	return response, 0, err
 }
 
 // Interface guards
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/libdns/libdns"
+	"net/netip"
 	"time"
 )
 
@@ -27,13 +28,10 @@ func main() {
 
 	//Create a new record...
 	newlyCreatedRecords, err := provider.AppendRecords(context.Background(), zone, []libdns.Record{
-		{
-			ID:       "",
-			Type:     "A",
-			Name:     "sub",
-			Value:    "1.2.3.4",
-			TTL:      1800 * time.Second,
-			Priority: 0,
+		libdns.Address{
+			Name: "sub",
+			IP:   netip.MustParseAddr("1.2.3.4"),
+			TTL:  1800 * time.Second,
 		},
 	})
 
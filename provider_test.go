@@ -0,0 +1,636 @@
+package hosttech
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/libdns/libdns"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeHosttechAPI is an in-memory stub of the Hosttech records API
+type fakeHosttechAPI struct {
+	mutex    sync.Mutex
+	nextId   int
+	zones    []HosttechZone
+	records  map[string][]map[string]any
+	requests []string
+	// dnskeys are returned for zones with DNSSEC enabled
+	dnskeys []DNSKEY
+	// failingPosts is the number of POST requests that store the record but then fail with a server error
+	failingPosts int
+	// slowPosts is the number of POST requests that store the record but then respond only after postDelay
+	slowPosts int
+	postDelay time.Duration
+}
+
+func newFakeHosttechAPI(t *testing.T, zone string, records ...map[string]any) (*fakeHosttechAPI, *Provider) {
+	api := &fakeHosttechAPI{
+		nextId:  100,
+		zones:   []HosttechZone{{Id: 1, Name: zone}},
+		records: map[string][]map[string]any{zone: records},
+	}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	return api, &Provider{APIToken: "token", BaseURL: server.URL + "/api/user/v1/", HTTPClient: server.Client()}
+}
+
+func (f *fakeHosttechAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.requests = append(f.requests, r.Method+" "+r.URL.RequestURI())
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/user/v1/"), "/")
+	if len(parts) >= 2 {
+		if _, ok := f.records[parts[1]]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+	}
+	switch {
+	case len(parts) == 1 && parts[0] == "zones" && r.Method == http.MethodGet:
+		f.respond(w, http.StatusOK, f.zones)
+	case len(parts) == 1 && parts[0] == "zones" && r.Method == http.MethodPost:
+		body, _ := io.ReadAll(r.Body)
+		zone := HosttechZone{}
+		_ = json.Unmarshal(body, &zone)
+		f.nextId++
+		zone.Id = uint(f.nextId)
+		f.zones = append(f.zones, zone)
+		f.records[zone.Name] = []map[string]any{}
+		f.respond(w, http.StatusCreated, zone)
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		f.respond(w, http.StatusOK, f.zones[f.findZone(parts[1])])
+	case len(parts) == 2 && r.Method == http.MethodPut:
+		i := f.findZone(parts[1])
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &f.zones[i])
+		f.respond(w, http.StatusOK, f.zones[i])
+	case len(parts) == 2 && r.Method == http.MethodDelete:
+		i := f.findZone(parts[1])
+		f.zones = append(f.zones[:i], f.zones[i+1:]...)
+		delete(f.records, parts[1])
+		w.WriteHeader(http.StatusNoContent)
+	case len(parts) == 3 && parts[2] == "dnssec" && r.Method == http.MethodGet:
+		info := DNSSECInfo{}
+		if f.zones[f.findZone(parts[1])].DNSSEC {
+			info.DNSKEYs = f.dnskeys
+		}
+		f.respond(w, http.StatusOK, info)
+	case len(parts) == 3 && parts[2] == "records" && r.Method == http.MethodGet:
+		recordType := r.URL.Query().Get("type")
+		records := []map[string]any{}
+		for _, record := range f.records[parts[1]] {
+			if recordType == "" || record["type"] == recordType {
+				records = append(records, record)
+			}
+		}
+		f.respond(w, http.StatusOK, records)
+	case len(parts) == 3 && parts[2] == "records" && r.Method == http.MethodPost:
+		record := f.decode(r)
+		f.nextId++
+		record["id"] = f.nextId
+		f.records[parts[1]] = append(f.records[parts[1]], record)
+		if f.failingPosts > 0 {
+			f.failingPosts--
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if f.slowPosts > 0 {
+			f.slowPosts--
+			// Other requests must not wait for the slow response
+			f.mutex.Unlock()
+			time.Sleep(f.postDelay)
+			f.mutex.Lock()
+		}
+		f.respond(w, http.StatusCreated, record)
+	case len(parts) == 4 && r.Method == http.MethodGet:
+		i := f.find(parts[1], parts[3])
+		if i < 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		f.respond(w, http.StatusOK, f.records[parts[1]][i])
+	case len(parts) == 4 && r.Method == http.MethodPut:
+		i := f.find(parts[1], parts[3])
+		if i < 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		record := f.decode(r)
+		record["id"] = f.records[parts[1]][i]["id"]
+		f.records[parts[1]][i] = record
+		f.respond(w, http.StatusOK, record)
+	case len(parts) == 4 && r.Method == http.MethodDelete:
+		i := f.find(parts[1], parts[3])
+		if i < 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		f.records[parts[1]] = append(f.records[parts[1]][:i], f.records[parts[1]][i+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeHosttechAPI) decode(r *http.Request) map[string]any {
+	body, _ := io.ReadAll(r.Body)
+	record := map[string]any{}
+	_ = json.Unmarshal(body, &record)
+	return record
+}
+
+func (f *fakeHosttechAPI) findZone(name string) int {
+	for i, zone := range f.zones {
+		if zone.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (f *fakeHosttechAPI) find(zone string, id string) int {
+	for i, record := range f.records[zone] {
+		if strconv.Itoa(toInt(record["id"])) == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (f *fakeHosttechAPI) respond(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+}
+
+func toInt(value any) int {
+	switch typed := value.(type) {
+	case int:
+		return typed
+	case float64:
+		return int(typed)
+	default:
+		return 0
+	}
+}
+
+func TestProvider_GetRecords(t *testing.T) {
+	_, provider := newFakeHosttechAPI(t, "example.com",
+		map[string]any{"id": 1, "type": "A", "name": "www", "ipv4": "192.0.2.1", "ttl": 3600},
+		map[string]any{"id": 2, "type": "TXT", "name": "www", "text": "hello world", "ttl": 600},
+	)
+
+	records, err := provider.GetRecords(context.Background(), "example.com.")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []libdns.Record{
+		libdns.Address{Name: "www", IP: netip.MustParseAddr("192.0.2.1"), TTL: time.Hour, ProviderData: 1},
+		libdns.TXT{Name: "www", Text: "hello world", TTL: 10 * time.Minute, ProviderData: 2},
+	}, records)
+}
+
+func TestProvider_SetRecords(t *testing.T) {
+	api, provider := newFakeHosttechAPI(t, "example.com",
+		map[string]any{"id": 1, "type": "A", "name": "www", "ipv4": "192.0.2.1", "ttl": 3600},
+		map[string]any{"id": 2, "type": "A", "name": "www", "ipv4": "192.0.2.2", "ttl": 3600},
+		map[string]any{"id": 3, "type": "TXT", "name": "www", "text": "hello world", "ttl": 3600},
+	)
+
+	records, err := provider.SetRecords(context.Background(), "example.com", []libdns.Record{
+		libdns.Address{Name: "www", IP: netip.MustParseAddr("192.0.2.3"), TTL: time.Hour},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []libdns.Record{
+		libdns.Address{Name: "www", IP: netip.MustParseAddr("192.0.2.3"), TTL: time.Hour, ProviderData: 1},
+	}, records)
+
+	remaining, err := provider.GetRecords(context.Background(), "example.com")
+	assert.NoError(t, err)
+	assert.Len(t, remaining, 2)
+	assert.Contains(t, api.requests, "DELETE /api/user/v1/zones/example.com/records/2")
+}
+
+func TestProvider_SetRecordsDifferentCase(t *testing.T) {
+	api, provider := newFakeHosttechAPI(t, "example.com",
+		map[string]any{"id": 1, "type": "A", "name": "www", "ipv4": "192.0.2.1", "ttl": 3600},
+	)
+
+	records, err := provider.SetRecords(context.Background(), "example.com", []libdns.Record{
+		libdns.Address{Name: "WWW", IP: netip.MustParseAddr("192.0.2.9"), TTL: time.Hour},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []libdns.Record{
+		libdns.Address{Name: "WWW", IP: netip.MustParseAddr("192.0.2.9"), TTL: time.Hour, ProviderData: 1},
+	}, records)
+
+	remaining, err := provider.GetRecords(context.Background(), "example.com")
+	assert.NoError(t, err)
+	assert.Len(t, remaining, 1)
+	assert.NotContains(t, api.requests, "POST /api/user/v1/zones/example.com/records")
+}
+
+func TestProvider_DeleteRecords(t *testing.T) {
+	_, provider := newFakeHosttechAPI(t, "example.com",
+		map[string]any{"id": 1, "type": "TXT", "name": "_acme-challenge", "text": "first", "ttl": 600},
+		map[string]any{"id": 2, "type": "TXT", "name": "_acme-challenge", "text": "second", "ttl": 600},
+		map[string]any{"id": 3, "type": "TXT", "name": "www", "text": "first", "ttl": 600},
+	)
+
+	deleted, err := provider.DeleteRecords(context.Background(), "example.com", []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge", Text: "first"},
+		libdns.TXT{Name: "missing", Text: "first"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge", Text: "first", TTL: 10 * time.Minute, ProviderData: 1},
+	}, deleted)
+
+	remaining, err := provider.GetRecords(context.Background(), "example.com")
+	assert.NoError(t, err)
+	assert.Len(t, remaining, 2)
+}
+
+func TestProvider_DeleteRecordsDifferentCase(t *testing.T) {
+	_, provider := newFakeHosttechAPI(t, "example.com",
+		map[string]any{"id": 1, "type": "CNAME", "name": "wWw", "cname": "site.example.net", "ttl": 600},
+	)
+
+	deleted, err := provider.DeleteRecords(context.Background(), "example.com", []libdns.Record{
+		libdns.CNAME{Name: "www"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []libdns.Record{
+		libdns.CNAME{Name: "wWw", Target: "site.example.net", TTL: 10 * time.Minute, ProviderData: 1},
+	}, deleted)
+}
+
+func TestProvider_Unauthorized(t *testing.T) {
+	_, provider := newFakeHosttechAPI(t, "example.com")
+	provider.APIToken = "wrong"
+
+	_, err := provider.ListZones(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, err.(ApiError).ErrorCode)
+}
+
+func TestProvider_AppendRecordsAfterAmbiguousFailure(t *testing.T) {
+	api, provider := newFakeHosttechAPI(t, "example.com",
+		map[string]any{"id": 1, "type": "TXT", "name": "_acme-challenge", "text": "token", "ttl": 600},
+	)
+	provider.Retry = &RetryPolicy{BaseDelay: time.Millisecond}
+	api.failingPosts = 1
+
+	records, err := provider.AppendRecords(context.Background(), "example.com", []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge", Text: "token"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge", Text: "token", TTL: 10 * time.Minute, ProviderData: 101},
+	}, records)
+
+	// The pre-existing record with the same value must not be mistaken for the created one, and no duplicate is created
+	all, err := provider.GetRecords(context.Background(), "example.com")
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+}
+
+func TestProvider_AppendRecordsAfterClientTimeout(t *testing.T) {
+	api, provider := newFakeHosttechAPI(t, "example.com")
+	provider.HTTPClient = &http.Client{Timeout: 50 * time.Millisecond}
+	api.slowPosts = 1
+	api.postDelay = 200 * time.Millisecond
+
+	records, err := provider.AppendRecords(context.Background(), "example.com", []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge", Text: "token"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge", Text: "token", TTL: 10 * time.Minute, ProviderData: 101},
+	}, records)
+	assert.Len(t, api.records["example.com"], 1)
+	assert.Equal(t, []string{
+		"POST /api/user/v1/zones/example.com/records",
+		"GET /api/user/v1/zones/example.com/records",
+	}, api.requests)
+}
+
+func TestProvider_AppendRecordsChecksAfterLastAttempt(t *testing.T) {
+	api, provider := newFakeHosttechAPI(t, "example.com")
+	provider.Retry = &RetryPolicy{MaxAttempts: 1}
+	api.failingPosts = 1
+
+	records, err := provider.AppendRecords(context.Background(), "example.com", []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge", Text: "token"},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Len(t, api.records["example.com"], 1)
+}
+
+func TestProvider_ValidationError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"message": "The given data was invalid.", "errors": {"text": ["The text may not be greater than 255 characters."]}}`))
+	}))
+	t.Cleanup(server.Close)
+	provider := &Provider{BaseURL: server.URL, HTTPClient: server.Client()}
+
+	records, err := provider.AppendRecords(context.Background(), "example.com", []libdns.Record{
+		libdns.TXT{Name: "www", Text: "too long", TTL: time.Hour},
+	})
+
+	assert.Empty(t, records)
+	var apiError ApiError
+	assert.True(t, errors.As(err, &apiError))
+	assert.Equal(t, http.StatusUnprocessableEntity, apiError.ErrorCode)
+	assert.Equal(t, "The given data was invalid.", apiError.Message)
+	assert.Equal(t, map[string][]string{"text": {"The text may not be greater than 255 characters."}}, apiError.Fields)
+	assert.Equal(t, `could not create record "www 3600 TXT too long": call to API was not successful, returned the status code '422 Unprocessable Entity': The given data was invalid. (text: The text may not be greater than 255 characters.)`, err.Error())
+}
+
+func TestProvider_AppendRecordsBatchError(t *testing.T) {
+	input := map[string]struct {
+		continueOnError bool
+		expectedCount   int
+	}{
+		"Stop at first failure": {continueOnError: false, expectedCount: 1},
+		"Continue on error":     {continueOnError: true, expectedCount: 2},
+	}
+
+	for name, testStruct := range input {
+		t.Run(name, func(t *testing.T) {
+			_, provider := newFakeHosttechAPI(t, "example.com")
+			provider.ContinueOnError = testStruct.continueOnError
+
+			records, err := provider.AppendRecords(context.Background(), "example.com", []libdns.Record{
+				libdns.TXT{Name: "first", Text: "1"},
+				libdns.RR{Type: "SSHFP", Name: "host", Data: "1 1 abcdef"},
+				libdns.TXT{Name: "third", Text: "3"},
+			})
+
+			assert.Len(t, records, testStruct.expectedCount)
+			var batchError BatchError
+			assert.True(t, errors.As(err, &batchError))
+			assert.Len(t, batchError.Errors, 1)
+			assert.Equal(t, libdns.RR{Type: "SSHFP", Name: "host", Data: "1 1 abcdef"}, batchError.Errors[0].Record)
+			assert.ErrorIs(t, err, ErrUnsupportedRecordType)
+		})
+	}
+}
+
+func TestProvider_DeleteRecordsBatchError(t *testing.T) {
+	input := map[string]struct {
+		continueOnError bool
+		expectedCount   int
+	}{
+		"Stop at first failure": {continueOnError: false, expectedCount: 0},
+		"Continue on error":     {continueOnError: true, expectedCount: 2},
+	}
+
+	for name, testStruct := range input {
+		t.Run(name, func(t *testing.T) {
+			_, provider := newFakeHosttechAPI(t, "example.com",
+				map[string]any{"id": 1, "type": "TXT", "name": "first", "text": "1", "ttl": 600},
+				map[string]any{"id": 2, "type": "TXT", "name": "third", "text": "3", "ttl": 600},
+			)
+			provider.ContinueOnError = testStruct.continueOnError
+
+			records, err := provider.DeleteRecords(context.Background(), "example.com", []libdns.Record{
+				libdns.TXT{Name: "first"},
+				libdns.TXT{Name: "www.example.org."},
+				libdns.TXT{Name: "third"},
+			})
+
+			assert.Len(t, records, testStruct.expectedCount)
+			var batchError BatchError
+			assert.True(t, errors.As(err, &batchError))
+			assert.Len(t, batchError.Errors, 1)
+			assert.Equal(t, libdns.TXT{Name: "www.example.org."}, batchError.Errors[0].Record)
+			assert.ErrorIs(t, err, ErrValidation)
+		})
+	}
+}
+
+func TestProvider_GetRecordsFiltered(t *testing.T) {
+	api, provider := newFakeHosttechAPI(t, "example.com",
+		map[string]any{"id": 1, "type": "TXT", "name": "_acme-challenge.api", "text": "token", "ttl": 600},
+		map[string]any{"id": 2, "type": "TXT", "name": "_acme-challenge", "text": "other token", "ttl": 600},
+		map[string]any{"id": 3, "type": "A", "name": "_acme-challenge.api", "ipv4": "192.0.2.1", "ttl": 600},
+		map[string]any{"id": 4, "type": "TXT", "name": "myapi", "text": "not a challenge", "ttl": 600},
+	)
+
+	records, err := provider.GetRecordsFiltered(context.Background(), "example.com", RecordFilter{Type: "txt", NameSuffix: "api"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge.api", Text: "token", TTL: 10 * time.Minute, ProviderData: 1},
+	}, records)
+	assert.Contains(t, api.requests, "GET /api/user/v1/zones/example.com/records?type=TXT")
+
+	records, err = provider.GetRecordsFiltered(context.Background(), "example.com", RecordFilter{Name: "_acme-challenge.api"})
+
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+}
+
+func TestProvider_GetRecordsFiltered_Names(t *testing.T) {
+	tests := map[string]struct {
+		filter      RecordFilter
+		expectedIDs []int
+		expectedErr error
+	}{
+		"fully-qualified name": {
+			filter:      RecordFilter{Name: "_ACME-challenge.api.Example.com."},
+			expectedIDs: []int{1, 3},
+		},
+		"name in other case": {
+			filter:      RecordFilter{Name: "_acme-challenge.API"},
+			expectedIDs: []int{1, 3},
+		},
+		"apex name": {
+			filter:      RecordFilter{Name: "@"},
+			expectedIDs: []int{5},
+		},
+		"fully-qualified suffix": {
+			filter:      RecordFilter{NameSuffix: "API.example.com."},
+			expectedIDs: []int{1, 3},
+		},
+		"suffix in other case": {
+			filter:      RecordFilter{NameSuffix: "Api"},
+			expectedIDs: []int{1, 3},
+		},
+		"zone apex as suffix": {
+			filter:      RecordFilter{NameSuffix: "example.com."},
+			expectedIDs: []int{1, 2, 3, 4, 5},
+		},
+		"suffix outside of zone": {
+			filter:      RecordFilter{NameSuffix: "api.example.org."},
+			expectedErr: ErrValidation,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, provider := newFakeHosttechAPI(t, "example.com",
+				map[string]any{"id": 1, "type": "TXT", "name": "_acme-challenge.api", "text": "token", "ttl": 600},
+				map[string]any{"id": 2, "type": "TXT", "name": "_acme-challenge", "text": "other token", "ttl": 600},
+				map[string]any{"id": 3, "type": "A", "name": "_Acme-Challenge.Api", "ipv4": "192.0.2.1", "ttl": 600},
+				map[string]any{"id": 4, "type": "TXT", "name": "myapi", "text": "not a challenge", "ttl": 600},
+				map[string]any{"id": 5, "type": "TXT", "name": "", "text": "apex", "ttl": 600},
+			)
+
+			records, err := provider.GetRecordsFiltered(context.Background(), "example.com", tc.filter)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			var ids []int
+			for _, record := range records {
+				ids = append(ids, idFromProviderData(providerData(record)))
+			}
+			assert.Equal(t, tc.expectedIDs, ids)
+		})
+	}
+}
+
+func TestProvider_GetRecord(t *testing.T) {
+	_, provider := newFakeHosttechAPI(t, "example.com",
+		map[string]any{"id": 7, "type": "MX", "ownername": "", "name": "mail.example.com", "pref": 10, "ttl": 3600, "comment": "mail"},
+	)
+
+	record, hosttechRecord, err := provider.GetRecord(context.Background(), "example.com", 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, libdns.MX{Name: "@", Target: "mail.example.com", Preference: 10, TTL: time.Hour, ProviderData: 7}, record)
+	assert.Equal(t, MXRecord{Base: Base{Id: 7, Type: "MX", TTL: 3600, Comment: "mail"}, Name: "mail.example.com", Pref: 10}, hosttechRecord)
+
+	_, _, err = provider.GetRecord(context.Background(), "example.com", 8)
+
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestProvider_CreateAndDeleteZone(t *testing.T) {
+	api, provider := newFakeHosttechAPI(t, "example.com")
+
+	zone, err := provider.CreateZone(context.Background(), HosttechZone{Name: "example.org.", Email: "admin@example.org", TTL: 3600, Nameserver: "ns1.hosttech.ch"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, HosttechZone{Id: 101, Name: "example.org", Email: "admin@example.org", TTL: 3600, Nameserver: "ns1.hosttech.ch"}, zone)
+
+	api.records["example.org"] = append(api.records["example.org"],
+		map[string]any{"id": 1, "type": "NS", "ownername": "", "targetname": "ns1.hosttech.ch", "ttl": 3600},
+		map[string]any{"id": 2, "type": "A", "name": "www", "ipv4": "192.0.2.1", "ttl": 3600},
+	)
+
+	err = provider.DeleteZone(context.Background(), "example.org", false)
+	assert.ErrorIs(t, err, ErrZoneNotEmpty)
+
+	_, err = provider.DeleteRecords(context.Background(), "example.org", []libdns.Record{libdns.Address{Name: "www"}})
+	assert.NoError(t, err)
+
+	err = provider.DeleteZone(context.Background(), "example.org", false)
+	assert.NoError(t, err)
+
+	err = provider.DeleteZone(context.Background(), "example.org", true)
+	assert.ErrorIs(t, err, ErrZoneNotFound)
+}
+
+func TestProvider_GetAndUpdateZone(t *testing.T) {
+	api, provider := newFakeHosttechAPI(t, "example.com")
+	api.zones[0] = HosttechZone{Id: 1, Name: "example.com", Email: "old@example.com", TTL: 3600, Nameserver: "ns1.hosttech.ch"}
+
+	zone, err := provider.GetZone(context.Background(), "example.com.")
+
+	assert.NoError(t, err)
+	assert.Equal(t, api.zones[0], zone)
+
+	zone, err = provider.UpdateZone(context.Background(), "example.com", ZoneSettings{Email: "new@example.com", TTL: 7200})
+
+	assert.NoError(t, err)
+	assert.Equal(t, HosttechZone{Id: 1, Name: "example.com", Email: "new@example.com", TTL: 7200, Nameserver: "ns1.hosttech.ch"}, zone)
+	assert.Equal(t, "PUT /api/user/v1/zones/example.com", api.requests[len(api.requests)-1])
+
+	_, err = provider.GetZone(context.Background(), "missing.com")
+	assert.ErrorIs(t, err, ErrZoneNotFound)
+}
+
+func TestProvider_DNSSEC(t *testing.T) {
+	api, provider := newFakeHosttechAPI(t, "example.com")
+	api.dnskeys = []DNSKEY{rootKSK}
+
+	zone, err := provider.EnableDNSSEC(context.Background(), "example.com", "hostmaster@example.com")
+
+	assert.NoError(t, err)
+	assert.True(t, zone.DNSSEC)
+	assert.Equal(t, "hostmaster@example.com", zone.DNSSECEmail)
+
+	info, err := provider.GetDNSSEC(context.Background(), "example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []DNSKEY{rootKSK}, info.DNSKEYs)
+	assert.Len(t, info.DSRecords, 1)
+	assert.Equal(t, uint16(20326), info.DSRecords[0].KeyTag)
+
+	zone, err = provider.DisableDNSSEC(context.Background(), "example.com")
+
+	assert.NoError(t, err)
+	assert.False(t, zone.DNSSEC)
+}
+
+func TestProvider_NormalizesNames(t *testing.T) {
+	api, provider := newFakeHosttechAPI(t, "example.com",
+		map[string]any{"id": 1, "type": "TXT", "name": "", "text": "v=spf1 -all", "ttl": 3600},
+	)
+
+	appended, err := provider.AppendRecords(context.Background(), "example.com.", []libdns.Record{
+		libdns.CNAME{Name: "www.example.com.", Target: "site.example.net."},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []libdns.Record{libdns.CNAME{Name: "www", Target: "site.example.net", TTL: 600 * time.Second, ProviderData: 101}}, appended)
+	assert.Equal(t, "www", api.records["example.com"][1]["name"])
+	assert.Equal(t, "site.example.net", api.records["example.com"][1]["cname"])
+
+	deleted, err := provider.DeleteRecords(context.Background(), "example.com", []libdns.Record{
+		libdns.TXT{Name: "example.com.", Text: "v=spf1 -all"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []libdns.Record{libdns.TXT{Name: "@", Text: "v=spf1 -all", TTL: time.Hour, ProviderData: 1}}, deleted)
+
+	// A fully-qualified name of another zone must not create a record in this zone
+	appended, err = provider.AppendRecords(context.Background(), "example.com", []libdns.Record{
+		libdns.TXT{Name: "x.otherexample.com.", Text: "token"},
+	})
+
+	assert.Empty(t, appended)
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.Len(t, api.records["example.com"], 1)
+}
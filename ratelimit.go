@@ -0,0 +1,143 @@
+package hosttech
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// RateLimit configures the client-side rate limit of the calls to the Hosttech API
+type RateLimit struct {
+	// RequestsPerSecond is the sustained number of requests per second
+	RequestsPerSecond float64 `json:"requests_per_second,omitempty"`
+	// Burst is the number of requests that can be sent at once after a pause. Defaults to 1.
+	Burst int `json:"burst,omitempty"`
+}
+
+// RateLimiter is a token bucket limiting the calls to the Hosttech API. It is safe for concurrent use and can be shared
+// between Provider values by assigning the same RateLimiter to each of them.
+// When Hosttech answers with 429 Too Many Requests, the limiter pauses all calls and halves its rate, which then
+// recovers step by step with every successful call.
+type RateLimiter struct {
+	mutex       sync.Mutex
+	limit       RateLimit
+	rate        float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+// NewRateLimiter returns a RateLimiter for the limit.
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+
+	return &RateLimiter{
+		limit:  limit,
+		rate:   limit.RequestsPerSecond,
+		tokens: float64(limit.Burst),
+		last:   time.Now(),
+	}
+}
+
+var (
+	sharedRateLimitersMutex sync.Mutex
+	sharedRateLimiters      = map[string]*RateLimiter{}
+)
+
+// SharedRateLimiter returns the RateLimiter of the process for the key, e.g. an account name, creating it with the
+// limit on first use. All providers using the same key share one budget.
+func SharedRateLimiter(key string, limit RateLimit) *RateLimiter {
+	sharedRateLimitersMutex.Lock()
+	defer sharedRateLimitersMutex.Unlock()
+
+	limiter, ok := sharedRateLimiters[key]
+	if !ok {
+		limiter = NewRateLimiter(limit)
+		sharedRateLimiters[key] = limiter
+	}
+	return limiter
+}
+
+// Wait blocks until a request may be sent, or the context is done.
+func (r *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := r.reserve()
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token if one is available and returns 0, otherwise it returns how long to wait for the next one
+func (r *RateLimiter) reserve() time.Duration {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+	r.tokens += now.Sub(r.last).Seconds() * r.rate
+	if r.tokens > float64(r.limit.Burst) {
+		r.tokens = float64(r.limit.Burst)
+	}
+	r.last = now
+
+	if now.Before(r.pausedUntil) {
+		return r.pausedUntil.Sub(now)
+	}
+	// Without a rate, only the pauses after rate limiting by Hosttech apply
+	if r.limit.RequestsPerSecond <= 0 {
+		return 0
+	}
+	if r.tokens >= 1 {
+		r.tokens--
+		return 0
+	}
+	return time.Duration((1 - r.tokens) / r.rate * float64(time.Second))
+}
+
+// throttle is called when Hosttech rejected a call because of rate limiting. It pauses all calls for the duration
+// Hosttech asked for, or a second if it did not, and halves the rate.
+func (r *RateLimiter) throttle(retryAfter time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if retryAfter <= 0 {
+		retryAfter = time.Second
+	}
+	if pausedUntil := time.Now().Add(retryAfter); pausedUntil.After(r.pausedUntil) {
+		r.pausedUntil = pausedUntil
+	}
+	r.tokens = 0
+	r.rate /= 2
+	if minimum := r.limit.RequestsPerSecond / 10; r.rate < minimum {
+		r.rate = minimum
+	}
+}
+
+// recover is called after each successful call and raises a throttled rate back towards the configured one
+func (r *RateLimiter) recover() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.rate += r.limit.RequestsPerSecond / 20
+	if r.rate > r.limit.RequestsPerSecond {
+		r.rate = r.limit.RequestsPerSecond
+	}
+}
+
+// rateLimiterKey derives the key of the shared rate limiter from the API token, without keeping the token itself
+func rateLimiterKey(apiToken string) string {
+	hash := sha256.Sum256([]byte(apiToken))
+	return hex.EncodeToString(hash[:])
+}
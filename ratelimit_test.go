@@ -0,0 +1,65 @@
+package hosttech
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{RequestsPerSecond: 50, Burst: 2})
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		assert.NoError(t, limiter.Wait(context.Background()))
+	}
+
+	// The burst is sent at once, the remaining 4 requests need 20ms each
+	assert.GreaterOrEqual(t, time.Since(start), 70*time.Millisecond)
+}
+
+func TestRateLimiter_WaitHonoursContext(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{RequestsPerSecond: 0.1})
+	assert.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, limiter.Wait(ctx), context.DeadlineExceeded)
+}
+
+func TestRateLimiter_ThrottleAndRecover(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{RequestsPerSecond: 10, Burst: 5})
+
+	limiter.throttle(20 * time.Millisecond)
+	assert.Equal(t, 5.0, limiter.rate)
+	assert.Greater(t, limiter.reserve(), time.Duration(0))
+
+	for i := 0; i < 20; i++ {
+		limiter.recover()
+	}
+	assert.Equal(t, 10.0, limiter.rate)
+}
+
+func TestSharedRateLimiter(t *testing.T) {
+	first := (&Provider{APIToken: "shared", RateLimit: &RateLimit{RequestsPerSecond: 5}}).rateLimiter()
+	second := (&Provider{APIToken: "shared", RateLimit: &RateLimit{RequestsPerSecond: 5}}).rateLimiter()
+	other := (&Provider{APIToken: "other", RateLimit: &RateLimit{RequestsPerSecond: 5}}).rateLimiter()
+
+	assert.Same(t, first, second)
+	assert.NotSame(t, first, other)
+	assert.Nil(t, (&Provider{APIToken: "shared"}).rateLimiter())
+}
+
+func TestProvider_ThrottlesOnTooManyRequests(t *testing.T) {
+	provider, requests := newFlakyServer(t, "", http.StatusTooManyRequests)
+	provider.RateLimiter = NewRateLimiter(RateLimit{RequestsPerSecond: 100, Burst: 10})
+
+	_, err := provider.ListZones(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), *requests)
+	assert.Less(t, provider.RateLimiter.rate, 100.0)
+}
@@ -0,0 +1,137 @@
+package hosttech
+
+import (
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// recordSetKey identifies an RRset by the relative name and the type of its records. The name is lower case, as
+// owner names are compared case-insensitively.
+type recordSetKey struct {
+	name       string
+	recordType string
+}
+
+// recordSetChange describes what has to happen for a single desired record.
+// If existing is nil, the desired record has to be created. Otherwise the existing record is updated in place,
+// unless unchanged is set, in which case it already matches the desired record.
+type recordSetChange struct {
+	desired   HosttechRecord
+	existing  HosttechRecord
+	unchanged bool
+}
+
+// recordSetPlan holds all changes needed to replace the RRsets of the desired records.
+// The changes are in the same order as the desired records.
+type recordSetPlan struct {
+	changes   []recordSetChange
+	deletions []HosttechRecord
+}
+
+// planRecordSets computes how the existing records of a zone have to be changed, so that for every (name, type) pair
+// of the desired records, exactly the desired records remain. Existing records with identical data are reused first,
+// the remaining existing records are updated in place and leftovers are deleted.
+func planRecordSets(zone string, existingRecords []HosttechRecord, desiredRecords []HosttechRecord) recordSetPlan {
+	existingBySet := map[recordSetKey][]int{}
+	for i, record := range existingRecords {
+		key := recordSetKeyOf(record.ToLibdnsRecord(zone).RR())
+		existingBySet[key] = append(existingBySet[key], i)
+	}
+
+	plan := recordSetPlan{changes: make([]recordSetChange, len(desiredRecords))}
+	used := make([]bool, len(existingRecords))
+	var setOrder []recordSetKey
+	seenSets := map[recordSetKey]bool{}
+
+	// First pass: reuse existing records with the same data
+	for i, desired := range desiredRecords {
+		desiredRR := desired.ToLibdnsRecord(zone).RR()
+		key := recordSetKeyOf(desiredRR)
+		if !seenSets[key] {
+			seenSets[key] = true
+			setOrder = append(setOrder, key)
+		}
+
+		plan.changes[i].desired = desired
+		for _, j := range existingBySet[key] {
+			existingRR := existingRecords[j].ToLibdnsRecord(zone).RR()
+			if used[j] || existingRR.Data != desiredRR.Data {
+				continue
+			}
+			used[j] = true
+			plan.changes[i].existing = existingRecords[j]
+			plan.changes[i].unchanged = existingRR.TTL == desiredRR.TTL
+			break
+		}
+	}
+
+	// Second pass: update leftover existing records in place
+	for i, change := range plan.changes {
+		if change.existing != nil {
+			continue
+		}
+		key := recordSetKeyOf(change.desired.ToLibdnsRecord(zone).RR())
+		for _, j := range existingBySet[key] {
+			if used[j] {
+				continue
+			}
+			used[j] = true
+			plan.changes[i].existing = existingRecords[j]
+			break
+		}
+	}
+
+	for _, key := range setOrder {
+		for _, j := range existingBySet[key] {
+			if !used[j] {
+				plan.deletions = append(plan.deletions, existingRecords[j])
+			}
+		}
+	}
+
+	return plan
+}
+
+func recordSetKeyOf(rr libdns.RR) recordSetKey {
+	return recordSetKey{name: strings.ToLower(rr.Name), recordType: rr.Type}
+}
+
+// recordMatches reports whether the existing record is described by the given libdns record.
+// If the libdns record carries a Hosttech id, only the record with that id matches. Otherwise the name has to be equal,
+// ignoring case, while the type, TTL and value are only compared if they are set.
+func recordMatches(zone string, existing HosttechRecord, record libdns.Record) bool {
+	if id := idFromProviderData(providerData(record)); id != 0 {
+		return existing.base().Id == id
+	}
+
+	existingRR := existing.ToLibdnsRecord(zone).RR()
+	rr := record.RR()
+
+	if !strings.EqualFold(existingRR.Name, rr.Name) {
+		return false
+	}
+	if rr.Type != "" && existingRR.Type != rr.Type {
+		return false
+	}
+	// Hosttech raises TTLs below the minimum, so the requested TTL is compared the way it would have been stored
+	if rr.TTL != 0 && existingRR.TTL != intSecondsToDuration(durationToIntSeconds(rr.TTL)) {
+		return false
+	}
+	if rr.Data != "" && existingRR.Data != rr.Data {
+		return false
+	}
+
+	return true
+}
+
+// createdBy reports whether the existing record was created from the requested record by the request with the marker
+func createdBy(zone string, existing HosttechRecord, requested HosttechRecord, marker string) bool {
+	existingRR := existing.ToLibdnsRecord(zone).RR()
+	requestedRR := requested.ToLibdnsRecord(zone).RR()
+
+	return strings.Contains(existing.base().Comment, marker) &&
+		existingRR.Name == requestedRR.Name &&
+		existingRR.Type == requestedRR.Type &&
+		existingRR.Data == requestedRR.Data
+}
@@ -0,0 +1,106 @@
+package hosttech
+
+import (
+	"github.com/libdns/libdns"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPlanRecordSets(t *testing.T) {
+	zone := "example.com"
+	existingRecords := []HosttechRecord{
+		ARecord{Base: Base{Id: 1, Type: "A", TTL: 3600}, Name: "www", IPV4: "192.0.2.1"},
+		ARecord{Base: Base{Id: 2, Type: "A", TTL: 3600}, Name: "www", IPV4: "192.0.2.2"},
+		ARecord{Base: Base{Id: 3, Type: "A", TTL: 3600}, Name: "www", IPV4: "192.0.2.3"},
+		TXTRecord{Base: Base{Id: 4, Type: "TXT", TTL: 3600}, Name: "www", Text: "hello world"},
+		ARecord{Base: Base{Id: 5, Type: "A", TTL: 3600}, Name: "other", IPV4: "192.0.2.9"},
+	}
+	desiredRecords := []HosttechRecord{
+		ARecord{Base: Base{Type: "A", TTL: 3600}, Name: "www", IPV4: "192.0.2.4"},
+		ARecord{Base: Base{Type: "A", TTL: 3600}, Name: "www", IPV4: "192.0.2.2"},
+		TXTRecord{Base: Base{Type: "TXT", TTL: 600}, Name: "www", Text: "hello world"},
+		TXTRecord{Base: Base{Type: "TXT", TTL: 600}, Name: "new", Text: "fresh"},
+	}
+
+	plan := planRecordSets(zone, existingRecords, desiredRecords)
+
+	assert.Len(t, plan.changes, 4)
+
+	// 192.0.2.4 has no exact match, so it reuses the first leftover record of the RRset
+	assert.Equal(t, existingRecords[0], plan.changes[0].existing)
+	assert.False(t, plan.changes[0].unchanged)
+
+	// 192.0.2.2 already exists with the same TTL
+	assert.Equal(t, existingRecords[1], plan.changes[1].existing)
+	assert.True(t, plan.changes[1].unchanged)
+
+	// The TXT record exists, but its TTL changed
+	assert.Equal(t, existingRecords[3], plan.changes[2].existing)
+	assert.False(t, plan.changes[2].unchanged)
+
+	// There is no record named "new" yet
+	assert.Nil(t, plan.changes[3].existing)
+
+	// 192.0.2.3 is left over, "other" belongs to a different RRset and stays untouched
+	assert.Equal(t, []HosttechRecord{existingRecords[2]}, plan.deletions)
+}
+
+func TestRecordMatches(t *testing.T) {
+	zone := "example.com"
+	existing := TXTRecord{Base: Base{Id: 7, Type: "TXT", TTL: 600}, Name: "_acme-challenge", Text: "token"}
+
+	input := map[string]struct {
+		expectedResult bool
+		data           libdns.Record
+	}{
+		"Matching id": {
+			expectedResult: true,
+			data:           libdns.TXT{Name: "other", ProviderData: 7},
+		},
+		"Different id": {
+			expectedResult: false,
+			data:           libdns.TXT{Name: "_acme-challenge", Text: "token", ProviderData: 8},
+		},
+		"Name only": {
+			expectedResult: true,
+			data:           libdns.RR{Name: "_acme-challenge"},
+		},
+		"Name in other case": {
+			expectedResult: true,
+			data:           libdns.TXT{Name: "_ACME-Challenge", Text: "token"},
+		},
+		"Name and value": {
+			expectedResult: true,
+			data:           libdns.TXT{Name: "_acme-challenge", Text: "token"},
+		},
+		"TTL below the minimum": {
+			expectedResult: true,
+			data:           libdns.TXT{Name: "_acme-challenge", Text: "token", TTL: 60 * time.Second},
+		},
+		"Different TTL": {
+			expectedResult: false,
+			data:           libdns.TXT{Name: "_acme-challenge", Text: "token", TTL: time.Hour},
+		},
+		"Different value": {
+			expectedResult: false,
+			data:           libdns.TXT{Name: "_acme-challenge", Text: "other token"},
+		},
+		"Different type": {
+			expectedResult: false,
+			data:           libdns.CNAME{Name: "_acme-challenge"},
+		},
+		"Different name": {
+			expectedResult: false,
+			data:           libdns.TXT{Name: "www", Text: "token"},
+		},
+	}
+
+	for name, testStruct := range input {
+		t.Run(name, func(t *testing.T) {
+			output := recordMatches(zone, existing, testStruct.data)
+
+			assert.Equal(t, testStruct.expectedResult, output)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package hosttech
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	recordTypesMutex sync.RWMutex
+	// recordTypes maps the type names of the Hosttech API to the factories of their HosttechRecord implementations
+	recordTypes = map[string]func() HosttechRecord{
+		"AAAA":  func() HosttechRecord { return AAAARecord{} },
+		"A":     func() HosttechRecord { return ARecord{} },
+		"NS":    func() HosttechRecord { return NSRecord{} },
+		"CNAME": func() HosttechRecord { return CNAMERecord{} },
+		"MX":    func() HosttechRecord { return MXRecord{} },
+		"TXT":   func() HosttechRecord { return TXTRecord{} },
+		"SRV":   func() HosttechRecord { return SRVRecord{} },
+		"CAA":   func() HosttechRecord { return CAARecord{} },
+		"PTR":   func() HosttechRecord { return PTRRecord{} },
+		"TLSA":  func() HosttechRecord { return TLSARecord{} },
+	}
+)
+
+// RegisterRecordType registers the HosttechRecord implementation used to decode records of the given type from the
+// Hosttech API and to convert libdns records of that type. Registering a type that already exists replaces its
+// implementation, which allows overriding the built-in conversions.
+// The factory must return an empty record. Implementations outside of this package have to embed Base.
+func RegisterRecordType(recordType string, factory func() HosttechRecord) {
+	recordTypesMutex.Lock()
+	defer recordTypesMutex.Unlock()
+
+	recordTypes[strings.ToUpper(recordType)] = factory
+}
+
+// recordTypeFactory returns the factory registered for the type, or nil if the type is not registered.
+func recordTypeFactory(recordType string) func() HosttechRecord {
+	recordTypesMutex.RLock()
+	defer recordTypesMutex.RUnlock()
+
+	return recordTypes[strings.ToUpper(recordType)]
+}
+
+// unmarshalRecord decodes the JSON into a new record of the same type as the record returned by the factory.
+// Factories may return both values and pointers.
+func unmarshalRecord(factory func() HosttechRecord, b []byte) (HosttechRecord, error) {
+	recordType := reflect.TypeOf(factory())
+	if recordType.Kind() == reflect.Ptr {
+		record := reflect.New(recordType.Elem())
+		err := json.Unmarshal(b, record.Interface())
+		return record.Interface().(HosttechRecord), err
+	}
+
+	record := reflect.New(recordType)
+	err := json.Unmarshal(b, record.Interface())
+	return record.Elem().Interface().(HosttechRecord), err
+}
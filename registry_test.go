@@ -0,0 +1,65 @@
+package hosttech
+
+import (
+	"github.com/libdns/libdns"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// sshfpRecord is a record type that is only known to the tests
+type sshfpRecord struct {
+	Base
+	Name        string `json:"name,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+func (s sshfpRecord) ToLibdnsRecord(zone string) libdns.Record {
+	return libdns.RR{Type: "SSHFP", Name: libdns.RelativeName(s.Name, zone), Data: s.Fingerprint, TTL: intSecondsToDuration(s.TTL)}
+}
+
+func (s sshfpRecord) FromLibdnsRecord(record libdns.Record) (HosttechRecord, error) {
+	rr := record.RR()
+	s.Type = "SSHFP"
+	s.Name = rr.Name
+	s.Fingerprint = rr.Data
+	s.TTL = durationToIntSeconds(rr.TTL)
+
+	return s, nil
+}
+
+func TestRegisterRecordType(t *testing.T) {
+	input := map[string]struct {
+		factory func() HosttechRecord
+	}{
+		"Value factory": {
+			factory: func() HosttechRecord { return sshfpRecord{} },
+		},
+		"Pointer factory": {
+			factory: func() HosttechRecord { return &sshfpRecord{} },
+		},
+	}
+
+	for name, testStruct := range input {
+		t.Run(name, func(t *testing.T) {
+			RegisterRecordType("sshfp", testStruct.factory)
+			defer func() {
+				recordTypesMutex.Lock()
+				delete(recordTypes, "SSHFP")
+				recordTypesMutex.Unlock()
+			}()
+
+			output := HosttechRecordWrapper{}
+			err := output.UnmarshalJSON([]byte(`{ "id": 30, "type": "SSHFP", "name": "host", "fingerprint": "1 1 abcdef", "ttl": 3600 }`))
+
+			assert.NoError(t, err)
+			assert.IsType(t, testStruct.factory(), output.value)
+			assert.Equal(t, 30, output.base().Id)
+			assert.Equal(t, libdns.RR{Type: "SSHFP", Name: "host", Data: "1 1 abcdef", TTL: time.Hour}, output.ToLibdnsRecord("example.com"))
+
+			record, err := LibdnsRecordToHosttechRecordWrapper(libdns.RR{Type: "SSHFP", Name: "host", Data: "1 1 abcdef", TTL: time.Hour})
+			assert.NoError(t, err)
+			assert.Equal(t, sshfpRecord{Base: Base{Type: "SSHFP", TTL: 3600}, Name: "host", Fingerprint: "1 1 abcdef"}, record)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package hosttech
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how failed calls to the Hosttech API are retried. Network errors, rate limiting (429) and
+// server errors (5xx) are retried with an exponential backoff. Creating records is not idempotent, so after a network
+// or server error the records of the zone are listed first. Only if no record carries the marker of the failed request,
+// the POST request is sent again. Rate limited POST requests were not processed by Hosttech and are retried without
+// listing.
+// Fields left at their zero value use the defaults. Setting MaxAttempts to 1 disables retries.
+// Retries stop once the context of the call is done. Timeouts of the HTTPClient of the provider are network errors,
+// which are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximal number of attempts per call, including the first one. Defaults to 4.
+	MaxAttempts int `json:"max_attempts,omitempty"`
+	// BaseDelay is the delay before the first retry, which is doubled for each further retry. Defaults to 250ms.
+	BaseDelay time.Duration `json:"base_delay,omitempty"`
+	// MaxDelay caps the exponential backoff between two attempts. Defaults to 5s. A longer Retry-After sent by Hosttech
+	// is still honoured, unless waiting would exceed the deadline of the context.
+	MaxDelay time.Duration `json:"max_delay,omitempty"`
+	// Jitter is the fraction of each delay that is randomised, between 0 and 1. Defaults to 0.2, a negative value
+	// disables it.
+	Jitter float64 `json:"jitter,omitempty"`
+}
+
+var defaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   250 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+	Jitter:      0.2,
+}
+
+// withDefaults returns the policy with all unset fields replaced by the defaults
+func (r RetryPolicy) withDefaults() RetryPolicy {
+	if r.MaxAttempts <= 0 {
+		r.MaxAttempts = defaultRetryPolicy.MaxAttempts
+	}
+	if r.BaseDelay <= 0 {
+		r.BaseDelay = defaultRetryPolicy.BaseDelay
+	}
+	if r.MaxDelay <= 0 {
+		r.MaxDelay = defaultRetryPolicy.MaxDelay
+	}
+	switch {
+	case r.Jitter < 0:
+		r.Jitter = 0
+	case r.Jitter == 0 || r.Jitter > 1:
+		r.Jitter = defaultRetryPolicy.Jitter
+	}
+	return r
+}
+
+// retryable reports whether a call with the given method that failed with err may be sent again.
+// Only the context of the caller stops retries: a timeout of the HTTP client also matches context.DeadlineExceeded,
+// but is a network error like any other.
+func (r RetryPolicy) retryable(ctx context.Context, httpMethod string, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var apiError ApiError
+	if !errors.As(err, &apiError) {
+		// Network error, the request may or may not have reached Hosttech
+		return httpMethod != http.MethodPost
+	}
+
+	switch {
+	case apiError.ErrorCode == http.StatusTooManyRequests:
+		return true
+	case apiError.ErrorCode >= 500:
+		return httpMethod != http.MethodPost
+	default:
+		return false
+	}
+}
+
+// ambiguous reports whether a failed POST may nevertheless have been processed by Hosttech, which is the case for
+// network errors, including timeouts of the HTTP client, and server errors. If the context of the caller is done,
+// nothing can be checked anymore, so the error is not treated as ambiguous.
+func ambiguous(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var apiError ApiError
+	if !errors.As(err, &apiError) {
+		return true
+	}
+	return apiError.ErrorCode >= 500
+}
+
+// delay returns how long to wait before the next attempt, after the given number of attempts have failed.
+// A Retry-After duration sent by Hosttech takes precedence over the backoff and is not capped by MaxDelay.
+func (r RetryPolicy) delay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	delay := r.BaseDelay << (attempt - 1)
+	if delay > r.MaxDelay || delay <= 0 {
+		delay = r.MaxDelay
+	}
+	delay -= time.Duration(rand.Float64() * r.Jitter * float64(delay))
+
+	return delay
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a number of seconds or an HTTP date.
+// It returns 0 if the header is missing or invalid.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+// sleepContext waits for the duration, but returns early with false if the context is done
+// or its deadline would pass while waiting.
+func sleepContext(ctx context.Context, duration time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < duration {
+		return false
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
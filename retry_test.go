@@ -0,0 +1,201 @@
+package hosttech
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newFlakyServer returns a provider for a server that answers with the given status codes, followed by successful
+// responses. It also returns a pointer to the number of requests received.
+func newFlakyServer(t *testing.T, retryAfter string, statusCodes ...int) (*Provider, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(atomic.AddInt32(&requests, 1)) - 1
+		if i < len(statusCodes) {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(statusCodes[i])
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": []}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return &Provider{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Retry:      &RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+	}, &requests
+}
+
+func TestProvider_RetriesServerErrors(t *testing.T) {
+	provider, requests := newFlakyServer(t, "", http.StatusServiceUnavailable, http.StatusBadGateway)
+
+	_, err := provider.ListZones(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(requests))
+}
+
+func TestProvider_GivesUpAfterMaxAttempts(t *testing.T) {
+	provider, requests := newFlakyServer(t, "", 500, 500, 500, 500, 500)
+	provider.Retry.MaxAttempts = 2
+
+	_, err := provider.ListZones(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(requests))
+}
+
+func TestProvider_RetryCanBeDisabled(t *testing.T) {
+	provider, requests := newFlakyServer(t, "", http.StatusServiceUnavailable)
+	provider.Retry.MaxAttempts = 1
+
+	_, err := provider.ListZones(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+func TestProvider_HonoursRetryAfterBeyondMaxDelay(t *testing.T) {
+	provider, requests := newFlakyServer(t, "1", http.StatusTooManyRequests)
+
+	_, err := provider.ListZones(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(requests))
+}
+
+func TestProvider_DoesNotRetryRetryAfterBeyondDeadline(t *testing.T) {
+	provider, requests := newFlakyServer(t, "60", http.StatusTooManyRequests)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := provider.ListZones(ctx)
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+func TestProvider_RetriesClientTimeout(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte(`{"data": []}`))
+	}))
+	t.Cleanup(server.Close)
+	provider := &Provider{
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 50 * time.Millisecond},
+		Retry:      &RetryPolicy{BaseDelay: time.Millisecond},
+	}
+
+	_, err := provider.ListZones(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+func TestProvider_RetriedDeleteTreatsNotFoundAsDeleted(t *testing.T) {
+	provider, requests := newFlakyServer(t, "", http.StatusBadGateway, http.StatusNotFound)
+
+	err := provider.deleteRecord(context.Background(), "example.com", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(requests))
+
+	provider, _ = newFlakyServer(t, "", http.StatusNotFound)
+
+	err = provider.deleteRecord(context.Background(), "example.com", 1)
+
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestProvider_CapsPostAttempts(t *testing.T) {
+	provider, requests := newFlakyServer(t, "", 429, 429, 429, 429, 429, 429, 429, 429)
+
+	_, err := provider.createRecord(context.Background(), "example.com", TXTRecord{Base: Base{Type: "TXT"}, Name: "www", Text: "token"})
+
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, int32(4), atomic.LoadInt32(requests))
+}
+
+func TestProvider_DoesNotRetryPostOnServerError(t *testing.T) {
+	provider, requests := newFlakyServer(t, "", http.StatusInternalServerError)
+
+	_, err := provider.makeApiCall(context.Background(), http.MethodPost, provider.BaseURL+"/zones/example.com/records", []byte(`{}`))
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+func TestRetryPolicy_Retryable(t *testing.T) {
+	policy := defaultRetryPolicy
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	clientTimeout := fmt.Errorf("Client.Timeout exceeded: %w", context.DeadlineExceeded)
+
+	input := map[string]struct {
+		expectedResult bool
+		ctx            context.Context
+		method         string
+		err            error
+	}{
+		"GET on 503":            {expectedResult: true, ctx: context.Background(), method: http.MethodGet, err: ApiError{ErrorCode: 503}},
+		"GET on 404":            {expectedResult: false, ctx: context.Background(), method: http.MethodGet, err: ApiError{ErrorCode: 404}},
+		"POST on 429":           {expectedResult: true, ctx: context.Background(), method: http.MethodPost, err: ApiError{ErrorCode: 429}},
+		"POST on 502":           {expectedResult: false, ctx: context.Background(), method: http.MethodPost, err: ApiError{ErrorCode: 502}},
+		"PUT on network":        {expectedResult: true, ctx: context.Background(), method: http.MethodPut, err: errors.New("connection reset")},
+		"POST on network":       {expectedResult: false, ctx: context.Background(), method: http.MethodPost, err: errors.New("connection reset")},
+		"GET on client timeout": {expectedResult: true, ctx: context.Background(), method: http.MethodGet, err: clientTimeout},
+		"Cancelled context":     {expectedResult: false, ctx: cancelledCtx, method: http.MethodGet, err: context.Canceled},
+	}
+
+	for name, testStruct := range input {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testStruct.expectedResult, policy.retryable(testStruct.ctx, testStruct.method, testStruct.err))
+		})
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}.withDefaults()
+
+	delay := policy.delay(3, 0)
+	assert.LessOrEqual(t, delay, 400*time.Millisecond)
+	assert.GreaterOrEqual(t, delay, 320*time.Millisecond)
+
+	assert.LessOrEqual(t, policy.delay(10, 0), time.Second)
+	assert.Equal(t, 500*time.Millisecond, policy.delay(1, 500*time.Millisecond))
+	assert.Equal(t, 30*time.Second, policy.delay(1, 30*time.Second))
+
+	withoutJitter := RetryPolicy{BaseDelay: 100 * time.Millisecond, Jitter: -1}.withDefaults()
+	assert.Equal(t, 400*time.Millisecond, withoutJitter.delay(3, 0))
+}
+
+func TestAmbiguous(t *testing.T) {
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.True(t, ambiguous(context.Background(), fmt.Errorf("Client.Timeout exceeded: %w", context.DeadlineExceeded)))
+	assert.True(t, ambiguous(context.Background(), ApiError{ErrorCode: 502}))
+	assert.False(t, ambiguous(context.Background(), ApiError{ErrorCode: 429}))
+	assert.False(t, ambiguous(cancelledCtx, context.Canceled))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, 3*time.Second, parseRetryAfter("3"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(""))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon"))
+	assert.InDelta(t, float64(10*time.Second), float64(parseRetryAfter(time.Now().Add(10*time.Second).UTC().Format(http.TimeFormat))), float64(2*time.Second))
+}
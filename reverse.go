@@ -0,0 +1,57 @@
+package hosttech
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// ReverseName returns the fully qualified name of the PTR record for the address, e.g. "10.2.0.192.in-addr.arpa" for
+// 192.0.2.10 or the nibble format below "ip6.arpa" for IPv6 addresses.
+func ReverseName(addr netip.Addr) string {
+	return strings.Join(reverseLabels(addr), ".")
+}
+
+// ReverseZone returns the reverse zone of the network with the given prefix length containing the address, together
+// with the name of the PTR record relative to that zone. For IPv4 the prefix length has to be a multiple of 8, for
+// IPv6 a multiple of 4, as reverse zones can only be delegated at label boundaries.
+func ReverseZone(addr netip.Addr, prefixBits int) (zone string, name string, err error) {
+	addr = addr.Unmap()
+	if !addr.IsValid() {
+		return "", "", fmt.Errorf("invalid IP address")
+	}
+
+	bitsPerLabel := 8
+	if addr.Is6() {
+		bitsPerLabel = 4
+	}
+	if prefixBits <= 0 || prefixBits >= addr.BitLen() || prefixBits%bitsPerLabel != 0 {
+		return "", "", fmt.Errorf("prefix length %d is not supported for %s, it has to be a multiple of %d below %d", prefixBits, addr, bitsPerLabel, addr.BitLen())
+	}
+
+	labels := reverseLabels(addr)
+	// The labels are ordered from the host part to the suffix, so the first labels form the relative name
+	hostLabels := (addr.BitLen() - prefixBits) / bitsPerLabel
+
+	return strings.Join(labels[hostLabels:], "."), strings.Join(labels[:hostLabels], "."), nil
+}
+
+func reverseLabels(addr netip.Addr) []string {
+	addr = addr.Unmap()
+
+	var labels []string
+	if addr.Is4() {
+		octets := addr.As4()
+		for i := len(octets) - 1; i >= 0; i-- {
+			labels = append(labels, strconv.Itoa(int(octets[i])))
+		}
+		return append(labels, "in-addr", "arpa")
+	}
+
+	octets := addr.As16()
+	for i := len(octets) - 1; i >= 0; i-- {
+		labels = append(labels, strconv.FormatUint(uint64(octets[i]&0x0f), 16), strconv.FormatUint(uint64(octets[i]>>4), 16))
+	}
+	return append(labels, "ip6", "arpa")
+}
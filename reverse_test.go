@@ -0,0 +1,87 @@
+package hosttech
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/netip"
+	"testing"
+)
+
+func TestReverseName(t *testing.T) {
+	input := map[string]struct {
+		expectedResult string
+		data           netip.Addr
+	}{
+		"IPv4": {
+			expectedResult: "10.2.0.192.in-addr.arpa",
+			data:           netip.MustParseAddr("192.0.2.10"),
+		},
+		"IPv4 mapped IPv6": {
+			expectedResult: "10.2.0.192.in-addr.arpa",
+			data:           netip.MustParseAddr("::ffff:192.0.2.10"),
+		},
+		"IPv6": {
+			expectedResult: "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa",
+			data:           netip.MustParseAddr("2001:db8::1"),
+		},
+	}
+
+	for name, testStruct := range input {
+		t.Run(name, func(t *testing.T) {
+			output := ReverseName(testStruct.data)
+
+			assert.Equal(t, testStruct.expectedResult, output)
+		})
+	}
+}
+
+func TestReverseZone(t *testing.T) {
+	input := map[string]struct {
+		expectedZone string
+		expectedName string
+		expectError  bool
+		data         netip.Addr
+		prefixBits   int
+	}{
+		"IPv4 /24": {
+			expectedZone: "2.0.192.in-addr.arpa",
+			expectedName: "10",
+			data:         netip.MustParseAddr("192.0.2.10"),
+			prefixBits:   24,
+		},
+		"IPv4 /16": {
+			expectedZone: "0.192.in-addr.arpa",
+			expectedName: "10.2",
+			data:         netip.MustParseAddr("192.0.2.10"),
+			prefixBits:   16,
+		},
+		"IPv6 /48": {
+			expectedZone: "0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa",
+			expectedName: "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0",
+			data:         netip.MustParseAddr("2001:db8::1"),
+			prefixBits:   48,
+		},
+		"IPv4 prefix not on a label boundary": {
+			expectError: true,
+			data:        netip.MustParseAddr("192.0.2.10"),
+			prefixBits:  25,
+		},
+		"Invalid address": {
+			expectError: true,
+			prefixBits:  24,
+		},
+	}
+
+	for name, testStruct := range input {
+		t.Run(name, func(t *testing.T) {
+			zone, recordName, err := ReverseZone(testStruct.data, testStruct.prefixBits)
+
+			if testStruct.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testStruct.expectedZone, zone)
+			assert.Equal(t, testStruct.expectedName, recordName)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package hosttech
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Certificate usages of a TLSA record as defined in RFC 6698 and RFC 7218
+const (
+	TLSAUsagePKIXTA uint8 = 0
+	TLSAUsagePKIXEE uint8 = 1
+	TLSAUsageDANETA uint8 = 2
+	TLSAUsageDANEEE uint8 = 3
+)
+
+// Selectors of a TLSA record, defining which part of the certificate is matched
+const (
+	TLSASelectorCert uint8 = 0
+	TLSASelectorSPKI uint8 = 1
+)
+
+// Matching types of a TLSA record, defining how the selected data is presented
+const (
+	TLSAMatchingTypeFull   uint8 = 0
+	TLSAMatchingTypeSHA256 uint8 = 1
+	TLSAMatchingTypeSHA512 uint8 = 2
+)
+
+// TLSA holds the data of a TLSA record
+type TLSA struct {
+	Usage        uint8
+	Selector     uint8
+	MatchingType uint8
+	// CertificateData is the certificate association data as lowercase hex string
+	CertificateData string
+}
+
+// String returns the presentation form of the TLSA data, e.g. "3 1 1 d2abde24...".
+func (t TLSA) String() string {
+	return fmt.Sprintf("%d %d %d %s", t.Usage, t.Selector, t.MatchingType, t.CertificateData)
+}
+
+// ParseTLSA parses the presentation form of the TLSA data. The certificate association data may be split by whitespace.
+func ParseTLSA(text string) (TLSA, error) {
+	fields := strings.Fields(text)
+	if len(fields) < 4 {
+		return TLSA{}, fmt.Errorf(`TLSA data "%s" has to consist of usage, selector, matching type and certificate association data`, text)
+	}
+
+	var values [3]uint8
+	for i, field := range fields[:3] {
+		value, err := strconv.ParseUint(field, 10, 8)
+		if err != nil {
+			return TLSA{}, fmt.Errorf(`TLSA data "%s" contains the invalid number "%s"`, text, field)
+		}
+		values[i] = uint8(value)
+	}
+
+	tlsa := TLSA{
+		Usage:           values[0],
+		Selector:        values[1],
+		MatchingType:    values[2],
+		CertificateData: strings.ToLower(strings.Join(fields[3:], "")),
+	}
+
+	return tlsa, tlsa.validate()
+}
+
+func (t TLSA) validate() error {
+	if t.Usage > TLSAUsageDANEEE {
+		return fmt.Errorf("TLSA usage %d is not supported", t.Usage)
+	}
+	if t.Selector > TLSASelectorSPKI {
+		return fmt.Errorf("TLSA selector %d is not supported", t.Selector)
+	}
+	if t.MatchingType > TLSAMatchingTypeSHA512 {
+		return fmt.Errorf("TLSA matching type %d is not supported", t.MatchingType)
+	}
+
+	data, err := hex.DecodeString(t.CertificateData)
+	if err != nil {
+		return fmt.Errorf("TLSA certificate association data is not a valid hex string: %w", err)
+	}
+	if t.MatchingType == TLSAMatchingTypeSHA256 && len(data) != sha256.Size {
+		return fmt.Errorf("TLSA certificate association data has %d bytes, but a SHA-256 digest has %d", len(data), sha256.Size)
+	}
+	if t.MatchingType == TLSAMatchingTypeSHA512 && len(data) != sha512.Size {
+		return fmt.Errorf("TLSA certificate association data has %d bytes, but a SHA-512 digest has %d", len(data), sha512.Size)
+	}
+
+	return nil
+}
+
+// NewTLSA derives the TLSA data for the certificate. The selector decides whether the whole certificate or only its
+// public key is matched, the matching type whether the data is published in full or as SHA-256/SHA-512 digest.
+func NewTLSA(cert *x509.Certificate, usage uint8, selector uint8, matchingType uint8) (TLSA, error) {
+	var selected []byte
+	switch selector {
+	case TLSASelectorCert:
+		selected = cert.Raw
+	case TLSASelectorSPKI:
+		selected = cert.RawSubjectPublicKeyInfo
+	default:
+		return TLSA{}, fmt.Errorf("TLSA selector %d is not supported", selector)
+	}
+
+	var data []byte
+	switch matchingType {
+	case TLSAMatchingTypeFull:
+		data = selected
+	case TLSAMatchingTypeSHA256:
+		digest := sha256.Sum256(selected)
+		data = digest[:]
+	case TLSAMatchingTypeSHA512:
+		digest := sha512.Sum512(selected)
+		data = digest[:]
+	default:
+		return TLSA{}, fmt.Errorf("TLSA matching type %d is not supported", matchingType)
+	}
+
+	tlsa := TLSA{
+		Usage:           usage,
+		Selector:        selector,
+		MatchingType:    matchingType,
+		CertificateData: hex.EncodeToString(data),
+	}
+
+	return tlsa, tlsa.validate()
+}
+
+// NewTLSAFromPEMFile derives the TLSA data from the first certificate in the PEM file, which is the leaf certificate
+// in the chain files written by most ACME clients. See NewTLSA for the meaning of the parameters.
+func NewTLSAFromPEMFile(path string, usage uint8, selector uint8, matchingType uint8) (TLSA, error) {
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return TLSA{}, err
+	}
+
+	for {
+		var block *pem.Block
+		block, pemBytes = pem.Decode(pemBytes)
+		if block == nil {
+			return TLSA{}, fmt.Errorf(`no certificate found in "%s"`, path)
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return TLSA{}, err
+		}
+
+		return NewTLSA(cert, usage, selector, matchingType)
+	}
+}
@@ -0,0 +1,124 @@
+package hosttech
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseTLSA(t *testing.T) {
+	input := map[string]struct {
+		expectedResult TLSA
+		expectError    bool
+		data           string
+	}{
+		"SHA-256 of SPKI": {
+			expectedResult: TLSA{
+				Usage:           3,
+				Selector:        1,
+				MatchingType:    1,
+				CertificateData: "d2abde240d7cd3ee6b4b28c54df034b97983a1d16e8a410e4561cb106618e971",
+			},
+			data: "3 1 1 D2ABDE240D7CD3EE6B4B28C54DF034B9 7983A1D16E8A410E4561CB106618E971",
+		},
+		"Missing data": {
+			expectError: true,
+			data:        "3 1 1",
+		},
+		"Invalid usage": {
+			expectError: true,
+			data:        "4 1 1 d2abde240d7cd3ee6b4b28c54df034b97983a1d16e8a410e4561cb106618e971",
+		},
+		"Digest with wrong length": {
+			expectError: true,
+			data:        "3 1 1 d2abde24",
+		},
+		"Data is not hex": {
+			expectError: true,
+			data:        "3 0 0 not-hex",
+		},
+	}
+
+	for name, testStruct := range input {
+		t.Run(name, func(t *testing.T) {
+			output, err := ParseTLSA(testStruct.data)
+
+			if testStruct.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testStruct.expectedResult, output)
+		})
+	}
+}
+
+func TestNewTLSA(t *testing.T) {
+	cert, certPEM := generateTestCertificate(t)
+	spkiDigest := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	expectedResult := TLSA{
+		Usage:           TLSAUsageDANEEE,
+		Selector:        TLSASelectorSPKI,
+		MatchingType:    TLSAMatchingTypeSHA256,
+		CertificateData: hex.EncodeToString(spkiDigest[:]),
+	}
+
+	output, err := NewTLSA(cert, TLSAUsageDANEEE, TLSASelectorSPKI, TLSAMatchingTypeSHA256)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResult, output)
+
+	path := filepath.Join(t.TempDir(), "fullchain.pem")
+	assert.NoError(t, os.WriteFile(path, certPEM, 0600))
+
+	output, err = NewTLSAFromPEMFile(path, TLSAUsageDANEEE, TLSASelectorSPKI, TLSAMatchingTypeSHA256)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResult, output)
+
+	output, err = NewTLSA(cert, TLSAUsageDANEEE, TLSASelectorCert, TLSAMatchingTypeSHA512)
+	assert.NoError(t, err)
+	assert.Len(t, output.CertificateData, 128)
+}
+
+func generateTestCertificate(t *testing.T) (*x509.Certificate, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mail.example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestTLSARecord_MarshalJSON(t *testing.T) {
+	record := TLSARecord{
+		Base: Base{Id: 5, Type: "TLSA", TTL: 3600},
+		Name: "_25._tcp.mail",
+		TLSA: TLSA{Usage: 3, Selector: 1, MatchingType: 1, CertificateData: "d2abde240d7cd3ee6b4b28c54df034b97983a1d16e8a410e4561cb106618e971"},
+	}
+
+	output, err := json.Marshal(record)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id": 5, "type": "TLSA", "ttl": 3600, "name": "_25._tcp.mail", "text": "3 1 1 d2abde240d7cd3ee6b4b28c54df034b97983a1d16e8a410e4561cb106618e971"}`, string(output))
+}
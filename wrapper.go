@@ -10,6 +10,14 @@ type HosttechZoneListResponseWrapper struct {
 	Data []HosttechZone `json:"data"`
 }
 
+type HosttechZoneSingleResponseWrapper struct {
+	Data HosttechZone `json:"data"`
+}
+
+type DNSSECInfoResponseWrapper struct {
+	Data DNSSECInfo `json:"data"`
+}
+
 type HosttechListResponseWrapper struct {
 	Data []HosttechRecordWrapper `json:"data"`
 }
@@ -22,12 +30,16 @@ type HosttechRecordWrapper struct {
 	value HosttechRecord
 }
 
-func (h HosttechRecordWrapper) toLibdnsRecord(zone string) libdns.Record {
-	return h.value.toLibdnsRecord(zone)
+func (h HosttechRecordWrapper) ToLibdnsRecord(zone string) libdns.Record {
+	return h.value.ToLibdnsRecord(zone)
 }
 
-func (h HosttechRecordWrapper) fromLibdnsRecord(record libdns.Record) {
-	h.value.fromLibdnsRecord(record)
+func (h HosttechRecordWrapper) base() Base {
+	return h.value.base()
+}
+
+func (h HosttechRecordWrapper) FromLibdnsRecord(record libdns.Record) (HosttechRecord, error) {
+	return h.value.FromLibdnsRecord(record)
 }
 
 func (h *HosttechRecordWrapper) UnmarshalJSON(b []byte) error {
@@ -36,66 +48,28 @@ func (h *HosttechRecordWrapper) UnmarshalJSON(b []byte) error {
 	if err != nil {
 		return err
 	}
-	switch base.Type {
-	case "AAAA":
-		record := AAAARecord{}
-		err = json.Unmarshal(b, &record)
-		h.value = HosttechRecord(record)
-	case "A":
-		record := ARecord{}
-		err = json.Unmarshal(b, &record)
-		h.value = HosttechRecord(record)
-	case "NS":
-		record := NSRecord{}
-		err = json.Unmarshal(b, &record)
-		h.value = HosttechRecord(record)
-	case "CNAME":
-		record := CNAMERecord{}
-		err = json.Unmarshal(b, &record)
-		h.value = HosttechRecord(record)
-	case "MX":
-		record := MXRecord{}
-		err = json.Unmarshal(b, &record)
-		h.value = HosttechRecord(record)
-	case "TXT":
-		record := TXTRecord{}
-		err = json.Unmarshal(b, &record)
-		h.value = HosttechRecord(record)
-	case "TLSA":
-		record := TLSARecord{}
-		err = json.Unmarshal(b, &record)
-		h.value = HosttechRecord(record)
-	default:
-		err = fmt.Errorf(`record type "%s" is not supported"`, base.Type)
+	factory := recordTypeFactory(base.Type)
+	if factory == nil {
+		h.value = newUnknownRecord(base, b)
+		return nil
 	}
+
+	h.value, err = unmarshalRecord(factory, b)
 	if err != nil {
-		return err
+		// A single record that can not be decoded must not break the listing of the whole zone
+		h.value = newUnknownRecord(base, b)
 	}
 
 	return nil
 }
 
 func LibdnsRecordToHosttechRecordWrapper(record libdns.Record) (HosttechRecord, error) {
-	var hosttechRecord HosttechRecord
-
-	switch record.Type {
-	case "AAAA":
-		hosttechRecord = AAAARecord{}
-	case "A":
-		hosttechRecord = ARecord{}
-	case "NS":
-		hosttechRecord = NSRecord{}
-	case "CNAME":
-		hosttechRecord = CNAMERecord{}
-	case "MX":
-		hosttechRecord = MXRecord{}
-	case "TXT":
-		hosttechRecord = TXTRecord{}
-	case "TLSA":
-		hosttechRecord = TLSARecord{}
-	default:
-		return nil, fmt.Errorf(`record type "%s" is not supported"`, record.Type)
+	recordType := record.RR().Type
+
+	factory := recordTypeFactory(recordType)
+	if factory == nil {
+		return nil, fmt.Errorf(`%w "%s"`, ErrUnsupportedRecordType, recordType)
 	}
 
-	return hosttechRecord.fromLibdnsRecord(record), nil
+	return factory().FromLibdnsRecord(record)
 }
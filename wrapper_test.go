@@ -1,8 +1,11 @@
 package hosttech
 
 import (
+	"github.com/libdns/libdns"
 	"github.com/stretchr/testify/assert"
+	"net/netip"
 	"testing"
+	"time"
 )
 
 func TestHosttechRecordWrapper_UnmarshalJSON(t *testing.T) {
@@ -114,6 +117,56 @@ func TestHosttechRecordWrapper_UnmarshalJSON(t *testing.T) {
 			},
 			data: []byte(`{ "id": 17, "type": "TXT", "name": "txt name", "text": "v=spf1 ip4:1.2.3.4/32 -all", "ttl": 3600, "comment": "my first record" }`),
 		},
+		"SRVRecord Test": {
+			expectedResult: HosttechRecordWrapper{
+				value: SRVRecord{
+					Base: Base{
+						Id:      18,
+						Type:    "SRV",
+						TTL:     3600,
+						Comment: "my first record",
+					},
+					Service:  "_xmpp-server._tcp",
+					Priority: 5,
+					Weight:   0,
+					Port:     5269,
+					Target:   "xmpp.example.com",
+				},
+			},
+			data: []byte(`{ "id": 18, "type": "SRV", "service": "_xmpp-server._tcp", "priority": 5, "weight": 0, "port": 5269, "target": "xmpp.example.com", "ttl": 3600, "comment": "my first record" }`),
+		},
+		"CAARecord Test": {
+			expectedResult: HosttechRecordWrapper{
+				value: CAARecord{
+					Base: Base{
+						Id:      19,
+						Type:    "CAA",
+						TTL:     3600,
+						Comment: "my first record",
+					},
+					Name:  "www",
+					Flag:  128,
+					Tag:   "iodef",
+					Value: "mailto:security@example.com",
+				},
+			},
+			data: []byte(`{ "id": 19, "type": "CAA", "name": "www", "flag": 128, "tag": "iodef", "value": "mailto:security@example.com", "ttl": 3600, "comment": "my first record" }`),
+		},
+		"PTRRecord Test": {
+			expectedResult: HosttechRecordWrapper{
+				value: PTRRecord{
+					Base: Base{
+						Id:      20,
+						Type:    "PTR",
+						TTL:     3600,
+						Comment: "my first record",
+					},
+					Origin: "10",
+					Name:   "host.example.com.",
+				},
+			},
+			data: []byte(`{ "id": 20, "type": "PTR", "origin": "10", "name": "host.example.com.", "ttl": 3600, "comment": "my first record" }`),
+		},
 		"TLSARecord Test": {
 			expectedResult: HosttechRecordWrapper{
 				value: TLSARecord{
@@ -124,7 +177,12 @@ func TestHosttechRecordWrapper_UnmarshalJSON(t *testing.T) {
 						Comment: "my first record",
 					},
 					Name: "tlsa name",
-					Text: "0 0 1 d2abde240d7cd3ee6b4b28c54df034b97983a1d16e8a410e4561cb106618e971",
+					TLSA: TLSA{
+						Usage:           0,
+						Selector:        0,
+						MatchingType:    1,
+						CertificateData: "d2abde240d7cd3ee6b4b28c54df034b97983a1d16e8a410e4561cb106618e971",
+					},
 				},
 			},
 			data: []byte(`{ "id": 17, "type": "TLSA", "name": "tlsa name", "text": "0 0 1 d2abde240d7cd3ee6b4b28c54df034b97983a1d16e8a410e4561cb106618e971", "ttl": 3600, "comment": "my first record" }`),
@@ -144,3 +202,109 @@ func TestHosttechRecordWrapper_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestHosttechRecordWrapper_UnmarshalJSON_UnknownRecord(t *testing.T) {
+	input := map[string]struct {
+		expectedResult libdns.Record
+		data           []byte
+	}{
+		"Unknown type": {
+			expectedResult: libdns.RR{
+				Type: "SSHFP",
+				Name: "host",
+				Data: "1 1 123456789abcdef67890123456789abcdef67890",
+				TTL:  3600 * time.Second,
+			},
+			data: []byte(`{ "id": 21, "type": "SSHFP", "name": "host.example.com", "algorithm": 1, "fptype": 1, "fingerprint": "123456789abcdef67890123456789abcdef67890", "ttl": 3600, "comment": null }`),
+		},
+		"Known type that can not be decoded": {
+			expectedResult: libdns.RR{
+				Type: "TLSA",
+				Name: "tlsa",
+				Data: "not a tlsa record",
+				TTL:  3600 * time.Second,
+			},
+			data: []byte(`{ "id": 22, "type": "TLSA", "name": "tlsa", "text": "not a tlsa record", "ttl": 3600 }`),
+		},
+	}
+
+	for name, testStruct := range input {
+		t.Run(name, func(t *testing.T) {
+			output := HosttechRecordWrapper{}
+			err := output.UnmarshalJSON(testStruct.data)
+
+			assert.NoError(t, err)
+			assert.IsType(t, UnknownRecord{}, output.value)
+			assert.JSONEq(t, string(testStruct.data), string(output.value.(UnknownRecord).Raw))
+			assert.Equal(t, testStruct.expectedResult, output.ToLibdnsRecord("example.com"))
+
+			_, err = output.FromLibdnsRecord(testStruct.expectedResult)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLibdnsRecordToHosttechRecordWrapper(t *testing.T) {
+	zone := "example.com"
+	input := map[string]struct {
+		data libdns.Record
+	}{
+		"ARecord Test": {
+			data: libdns.Address{Name: "sub", IP: netip.MustParseAddr("1.2.3.4"), TTL: 1800 * time.Second, ProviderData: 10},
+		},
+		"AAAARecord Test": {
+			data: libdns.Address{Name: "sub", IP: netip.MustParseAddr("2001:db8:1234::1"), TTL: 1800 * time.Second, ProviderData: 11},
+		},
+		"NSRecord Test": {
+			data: libdns.NS{Name: "sub", Target: "ns1.example.com", TTL: 1800 * time.Second, ProviderData: 12},
+		},
+		"CNAMERecord Test": {
+			data: libdns.CNAME{Name: "sub", Target: "site.example.com", TTL: 1800 * time.Second, ProviderData: 13},
+		},
+		"MXRecord Test": {
+			data: libdns.MX{Name: "sub", Target: "mail.example.com", Preference: 10, TTL: 1800 * time.Second, ProviderData: 14},
+		},
+		"TXTRecord Test": {
+			data: libdns.TXT{Name: "sub", Text: "v=spf1 -all", TTL: 1800 * time.Second, ProviderData: 15},
+		},
+		"SRVRecord Test": {
+			data: libdns.SRV{Service: "sip", Transport: "tcp", Name: "sub", Priority: 10, Weight: 20, Port: 5060, Target: "sip.example.com", TTL: 1800 * time.Second, ProviderData: 16},
+		},
+		"SRVRecord at apex Test": {
+			data: libdns.SRV{Service: "xmpp-server", Transport: "tcp", Name: "@", Priority: 5, Port: 5269, Target: "xmpp.example.com", TTL: 1800 * time.Second, ProviderData: 17},
+		},
+		"CAARecord Test": {
+			data: libdns.CAA{Name: "sub", Flags: 0, Tag: "issuewild", Value: "letsencrypt.org", TTL: 1800 * time.Second, ProviderData: 18},
+		},
+		"PTRRecord Test": {
+			data: libdns.RR{Type: "PTR", Name: "10", Data: "host.example.com", TTL: 1800 * time.Second},
+		},
+		"TLSARecord Test": {
+			data: libdns.RR{Type: "TLSA", Name: "sub", Data: "3 1 1 d2abde240d7cd3ee6b4b28c54df034b97983a1d16e8a410e4561cb106618e971", TTL: 1800 * time.Second},
+		},
+	}
+
+	for name, testStruct := range input {
+		t.Run(name, func(t *testing.T) {
+			output, err := LibdnsRecordToHosttechRecordWrapper(testStruct.data)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testStruct.data, output.ToLibdnsRecord(zone))
+		})
+	}
+}
+
+func TestLibdnsRecordToHosttechRecordWrapper_ParsesRR(t *testing.T) {
+	output, err := LibdnsRecordToHosttechRecordWrapper(libdns.RR{Type: "MX", Name: "sub", Data: "10 mail.example.com", TTL: time.Hour})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "mail.example.com", output.(MXRecord).Name)
+	assert.Equal(t, uint16(10), output.(MXRecord).Pref)
+	assert.Equal(t, 3600, output.(MXRecord).TTL)
+}
+
+func TestLibdnsRecordToHosttechRecordWrapper_RejectsUnknownCAATag(t *testing.T) {
+	_, err := LibdnsRecordToHosttechRecordWrapper(libdns.CAA{Name: "@", Tag: "contactemail", Value: "admin@example.com"})
+
+	assert.Error(t, err)
+}